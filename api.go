@@ -0,0 +1,137 @@
+package errors
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync/atomic"
+)
+
+// ErrAPIBodyVersion is returned by APIBody and APIBodySchema for unknown versions.
+var ErrAPIBodyVersion = New("unsupported API body version %d", false)
+
+//go:embed schemas/api_body.v1.json
+var _apiBodySchemaV1 []byte
+
+var _messageMasking = func() *atomic.Bool {
+	messageMasking := &atomic.Bool{}
+	messageMasking.Store(true)
+
+	return messageMasking
+}()
+
+// SetMessageMasking sets whether to hide the wrapped errors from the API bodies,
+// whose messages may leak internal details, exposing only the code and message
+// of the outermost Error (default is enabled). Disabling it, such as during
+// development, exposes the codes and messages of the wrapped Errors as details.
+func SetMessageMasking(enabled bool) {
+	_messageMasking.Store(enabled)
+}
+
+// MessageMasking returns whether the wrapped errors are hidden from the API bodies
+// and public messages, so transports can apply the same policy to the information
+// they expose.
+func MessageMasking() bool {
+	return _messageMasking.Load()
+}
+
+// PublicMessage returns the message of the Error to expose outside the service,
+// such as in the headers and statuses of the transports: its own message followed
+// by the ones of the wrapped errors unless message masking is enabled (see
+// SetMessageMasking), never prefixed with the codes (see SetCodePrefix).
+func (self Error) PublicMessage() string {
+	message := self.formatted()
+
+	for err := self; !_messageMasking.Load() && err.cause != nil && !err.inlineCause; {
+		switch cause := err.cause.(type) {
+		case Error:
+			err = cause
+		case *Error:
+			err = *cause
+		default:
+			return message + ": " + cause.Error()
+		}
+
+		message += ": " + err.formatted()
+	}
+
+	return message
+}
+
+type apiBodyDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type apiBodyError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Details []apiBodyDetail `json:"details"`
+	TraceID string          `json:"trace_id,omitempty"`
+	EventID string          `json:"event_id,omitempty"`
+	DocsURL string          `json:"docs_url,omitempty"`
+}
+
+type apiBody struct {
+	Error apiBodyError `json:"error"`
+}
+
+func apiBodyCode(err Error) string {
+	if err.code == "" {
+		return "UNKNOWN"
+	}
+
+	return err.code
+}
+
+// APIBody returns the public JSON error body of the Error in the versioned
+// envelope described by APIBodySchema, so all services expose byte-identical
+// error payloads. The wrapped Errors are only listed as details if message
+// masking is disabled (see SetMessageMasking). Only version 1 exists.
+func (self Error) APIBody(version int) ([]byte, error) {
+	if version != 1 {
+		return nil, ErrAPIBodyVersion.Raise(version)
+	}
+
+	body := apiBody{
+		Error: apiBodyError{
+			Code:    apiBodyCode(self),
+			Message: self.formatted(),
+			Details: make([]apiBodyDetail, 0),
+			TraceID: self.tags["trace_id"],
+			EventID: self.sentryEventID,
+			DocsURL: self.docs,
+		},
+	}
+
+	for cause := self.cause; cause != nil && !_messageMasking.Load(); {
+		var next Error
+
+		switch err := cause.(type) {
+		case Error:
+			next = err
+		case *Error:
+			next = *err
+		default:
+			cause = nil
+			continue
+		}
+
+		body.Error.Details = append(body.Error.Details, apiBodyDetail{
+			Code:    apiBodyCode(next),
+			Message: next.formatted(),
+		})
+
+		cause = next.cause
+	}
+
+	return json.Marshal(body)
+}
+
+// APIBodySchema returns the JSON Schema of the version of the API body.
+func APIBodySchema(version int) ([]byte, error) {
+	if version != 1 {
+		return nil, ErrAPIBodyVersion.Raise(version)
+	}
+
+	return append([]byte(nil), _apiBodySchemaV1...), nil
+}
@@ -0,0 +1,53 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrPaymentDeclined = errors.NewWithOptions("payment declined", errors.NewOptions{Code: "PAYMENT_DECLINED"})
+
+// nolint:paralleltest
+func TestAPIBody(t *testing.T) {
+	defer errors.SetMessageMasking(true)
+
+	err := ErrPaymentDeclined.Raise().
+		Cause(ErrUserNotFound.Raise("Alex").Cause(ErrOtherLibrary)).
+		Tags(map[string]any{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"})
+
+	body, berr := err.APIBody(1)
+	if berr != nil {
+		t.FailNow()
+	}
+
+	expected := `{"error":{"code":"PAYMENT_DECLINED","message":"payment declined","details":[],` +
+		`"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}}`
+	if string(body) != expected {
+		t.FailNow()
+	}
+
+	errors.SetMessageMasking(false)
+
+	body, berr = err.APIBody(1)
+	if berr != nil {
+		t.FailNow()
+	}
+
+	expected = `{"error":{"code":"PAYMENT_DECLINED","message":"payment declined",` +
+		`"details":[{"code":"UNKNOWN","message":"user Alex not found"}],` +
+		`"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}}`
+	if string(body) != expected {
+		t.FailNow()
+	}
+
+	if _, berr := err.APIBody(2); !errors.ErrAPIBodyVersion.Is(berr) {
+		t.FailNow()
+	}
+
+	schema, serr := errors.APIBodySchema(1)
+	if serr != nil || !json.Valid(schema) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,21 @@
+package errors
+
+// Canonical Errors shared across services, with HTTP and gRPC/Connect codes wired
+// by the errorshttp and errorsconnect packages. They can be raised as is or used
+// as causes of more specific Errors.
+var (
+	// NotFound is raised when a requested entity does not exist.
+	NotFound = NewWithOptions("not found", NewOptions{Code: "NOT_FOUND"})
+	// Conflict is raised when an operation conflicts with the current state,
+	// such as an entity that already exists.
+	Conflict = NewWithOptions("conflict", NewOptions{Code: "CONFLICT"})
+	// Unauthorized is raised when the caller is not authenticated.
+	Unauthorized = NewWithOptions("unauthorized", NewOptions{Code: "UNAUTHORIZED"})
+	// InvalidArgument is raised when the caller specified an invalid argument.
+	InvalidArgument = NewWithOptions("invalid argument", NewOptions{Code: "INVALID_ARGUMENT"})
+	// ResourceExhausted is raised when a resource, such as memory, a quota or file
+	// descriptors, has been exhausted.
+	ResourceExhausted = NewWithOptions("resource exhausted", NewOptions{Code: "RESOURCE_EXHAUSTED"})
+	// Internal is raised when an unexpected internal failure happens.
+	Internal = NewWithOptions("internal error", NewOptions{Code: "INTERNAL"})
+)
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	goerrors "errors"
+	"strconv"
+	"sync/atomic"
+)
+
+const _DEFAULT_MAX_CHAIN_DEPTH = 64
+
+// ErrChainCycle replaces a cause that would make an Error wrap itself.
+var ErrChainCycle = New("error chain cycle detected", false)
+
+var _maxChainDepth = func() *atomic.Int64 {
+	maxChainDepth := &atomic.Int64{}
+	maxChainDepth.Store(_DEFAULT_MAX_CHAIN_DEPTH)
+
+	return maxChainDepth
+}()
+
+// SetMaxChainDepth sets the maximum number of errors of a chain walked by Has,
+// Summary, Hash or Causes and rendered in the reports, marking the rest as
+// truncated (default is 64).
+func SetMaxChainDepth(depth int) {
+	_maxChainDepth.Store(int64(max(depth, 1)))
+}
+
+func maxChainDepth() int {
+	return int(_maxChainDepth.Load())
+}
+
+func truncatedChain(depth int) string {
+	return "[... chain truncated at " + strconv.Itoa(depth) + " errors]"
+}
+
+// chainContains checks whether the raised Error is wrapped inside an error.
+func chainContains(err error, target *Error) bool {
+	for depth := 0; err != nil && depth < maxChainDepth(); depth++ {
+		switch cause := err.(type) {
+		case *Error:
+			if cause == target {
+				return true
+			}
+
+			err = cause.cause
+		case Error:
+			err = cause.cause
+		default:
+			err = goerrors.Unwrap(err)
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"context"
+	goerrors "errors"
+	"sync"
+	"time"
+)
+
+var _contextTaggers = struct {
+	sync.RWMutex
+	taggers []func(ctx context.Context) map[string]any
+}{}
+
+// AddContextTagger adds a function that extracts tags from the context of the
+// Errors raised with RaiseCtx, such as trace identifiers or tenants.
+func AddContextTagger(tagger func(ctx context.Context) map[string]any) {
+	_contextTaggers.Lock()
+	defer _contextTaggers.Unlock()
+
+	_contextTaggers.taggers = append(_contextTaggers.taggers, tagger)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx serving the tenant, so the Errors raised with
+// RaiseCtx while serving it are tagged with its identifier as tenant, enabling
+// per-tenant error dashboards and filtering in services such as Sentry.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the identifier of the tenant served by ctx set with
+// WithTenant, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantKey{}).(string)
+
+	return tenantID, ok && tenantID != ""
+}
+
+// tagContext tags the raised Error with the tenant served by the context and the
+// tags extracted from it by the taggers added with AddContextTagger.
+func tagContext(ctx context.Context, err *Error) *Error {
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		err.Tags(map[string]any{"tenant": tenantID})
+	}
+
+	_contextTaggers.RLock()
+	defer _contextTaggers.RUnlock()
+
+	for _, tagger := range _contextTaggers.taggers {
+		if tags := tagger(ctx); len(tags) > 0 {
+			err.Tags(tags)
+		}
+	}
+
+	return err
+}
+
+// ErrCanceled is raised by CancelCause when a context was canceled without an Error.
+var ErrCanceled = New("context canceled")
+
+// WithCancelCause returns a copy of parent that is canceled with a raised Error as
+// its cause, which can be read back with CancelCause. Canceling with a nil Error
+// cancels with context.Canceled, avoiding typed nil causes.
+func WithCancelCause(parent context.Context) (context.Context, func(err *Error)) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	return ctx, func(err *Error) {
+		if err == nil {
+			cancel(nil)
+			return
+		}
+
+		cancel(err)
+	}
+}
+
+// CancelCause returns the raised Error that canceled the context, including its
+// stack trace and extra information, or nil if the context is not canceled. When
+// the context was canceled with another error, ErrCanceled is raised with it as
+// the cause.
+func CancelCause(ctx context.Context) *Error {
+	cause := context.Cause(ctx)
+	if cause == nil {
+		return nil
+	}
+
+	var err *Error
+	if goerrors.As(cause, &err) {
+		return err
+	}
+
+	return ErrCanceled.Raise().Cause(cause).Skip(1)
+}
+
+// ErrTimeout is raised by WithTimeout when the call does not finish in time.
+var ErrTimeout = New("call timed out after %s")
+
+// WithTimeout runs fn with a copy of ctx that expires after the timeout. When the
+// deadline expires, it returns a raised ErrTimeout with the timeout and elapsed
+// time as extra, the stack trace of the call site and context.DeadlineExceeded as
+// its cause, without waiting for fn to return, so fn must honour the context to
+// not leak. Otherwise, the error of fn or the cause of the parent's cancelation is
+// returned as is, also when the parent's deadline is the one that expired first.
+func WithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	parent := ctx
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	}
+
+	if err == nil || !goerrors.Is(err, context.DeadlineExceeded) || ctx.Err() == nil || parent.Err() != nil {
+		return err
+	}
+
+	return ErrTimeout.Raise(timeout).Extra(map[string]any{
+		"timeout": timeout.String(),
+		"elapsed": time.Since(start).String(),
+	}).Cause(err).Skip(1)
+}
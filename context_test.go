@@ -0,0 +1,106 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestCancelCause(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := errors.WithCancelCause(context.Background())
+
+	if errors.CancelCause(ctx) != nil {
+		t.FailNow()
+	}
+
+	err := ErrUserNotFound.Raise("Alex")
+	cancel(err)
+
+	if errors.CancelCause(ctx) != err {
+		t.FailNow()
+	}
+
+	ctx, cancel = errors.WithCancelCause(context.Background())
+	cancel(nil)
+
+	if !errors.ErrCanceled.Is(errors.CancelCause(ctx)) || !errors.CancelCause(ctx).Has(context.Canceled) {
+		t.FailNow()
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	err := errors.WithTimeout(context.Background(), time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+
+		return nil
+	})
+
+	var rerr *errors.Error
+	if !goerrors.As(err, &rerr) || !errors.ErrTimeout.Is(rerr) || !goerrors.Is(err, context.DeadlineExceeded) {
+		t.FailNow()
+	}
+
+	if err.Error() != "call timed out after 1ms: context deadline exceeded" {
+		t.FailNow()
+	}
+
+	if !strings.HasSuffix(rerr.StackTrace()[0].Function, ".TestWithTimeout") {
+		t.FailNow()
+	}
+
+	if _, ok := errors.GetExtraAs[string](err, "elapsed"); !ok {
+		t.FailNow()
+	}
+
+	err = errors.WithTimeout(context.Background(), time.Second, func(context.Context) error {
+		return ErrOtherLibrary
+	})
+	if err != ErrOtherLibrary {
+		t.FailNow()
+	}
+
+	parent, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err = errors.WithTimeout(parent, time.Minute, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err != context.DeadlineExceeded || errors.ErrTimeout.In(err) {
+		t.FailNow()
+	}
+}
+
+func TestWithTenant(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := errors.TenantFromContext(context.Background()); ok {
+		t.FailNow()
+	}
+
+	ctx := errors.WithTenant(context.Background(), "acme")
+
+	if tenantID, ok := errors.TenantFromContext(ctx); !ok || tenantID != "acme" {
+		t.FailNow()
+	}
+
+	err := ErrUserNotFound.RaiseCtx(ctx, "Alex")
+	if err.GetTags()["tenant"] != "acme" || err.SentryReport().Tags["tenant"] != "acme" {
+		t.FailNow()
+	}
+
+	if _, ok := ErrUserNotFound.Raise("Alex").GetTags()["tenant"]; ok {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,162 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+type detail = any
+
+// DetailCodec renders and encodes the detail messages added to the Errors with
+// Detail, so the package doesn't depend on their format, such as the protobuf
+// messages of the Google API error model (see errorsdetails).
+type DetailCodec interface {
+	// String renders the detail message in the string reports and Sentry events.
+	String(message any) string
+	// Encode encodes the detail message for the gob encoding.
+	Encode(message any) ([]byte, error)
+	// Decode decodes a detail message encoded by Encode.
+	Decode(data []byte) (any, error)
+	// EncodeJSON encodes the detail message for the JSON encoding.
+	EncodeJSON(message any) ([]byte, error)
+}
+
+// ErrDetailEncoding is returned by the default DetailCodec, which cannot encode
+// detail messages of unknown types for the gob encoding.
+var ErrDetailEncoding = New("cannot encode detail message of type %T", false)
+
+type defaultDetailCodec struct{}
+
+func (self defaultDetailCodec) String(message any) string {
+	return fmt.Sprintf("%v", message)
+}
+
+func (self defaultDetailCodec) Encode(message any) ([]byte, error) {
+	return nil, ErrDetailEncoding.Raise(message)
+}
+
+func (self defaultDetailCodec) Decode(_ []byte) (any, error) {
+	return nil, ErrDetailEncoding.Raise(nil)
+}
+
+func (self defaultDetailCodec) EncodeJSON(message any) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+var _detailCodec = func() *atomic.Pointer[DetailCodec] {
+	codec := DetailCodec(defaultDetailCodec{})
+	detailCodec := &atomic.Pointer[DetailCodec]{}
+	detailCodec.Store(&codec)
+
+	return detailCodec
+}()
+
+// SetDetailCodec sets the DetailCodec of the detail messages added to the Errors,
+// such as errorsdetails.Codec for the google.rpc messages (default renders them
+// with their default format, encodes them as JSON and skips them in the gob
+// encoding).
+func SetDetailCodec(codec DetailCodec) {
+	if codec == nil {
+		codec = defaultDetailCodec{}
+	}
+
+	_detailCodec.Store(&codec)
+}
+
+// Detail adds a detail message to the raised Error, such as a google.rpc
+// BadRequest, RetryInfo or ErrorInfo of the Google API error model, which is
+// attached to the gRPC and Connect statuses converted from it (see
+// errorsconnect) and rendered in the string reports with the DetailCodec.
+func (self *Error) Detail(message any) *Error {
+	if message != nil {
+		self.details = append(self.details, message)
+	}
+
+	return self
+}
+
+// Details returns the detail messages of the raised Error.
+func (self Error) Details() []any {
+	return append([]any(nil), self.details...)
+}
+
+// AllDetails returns the detail messages of all errors wrapped within the Error,
+// the outermost ones first.
+func (self Error) AllDetails() []any {
+	var details []any
+
+	for _, err := range self.chain() {
+		details = append(details, err.details...)
+	}
+
+	return details
+}
+
+// stringDetails returns the rendered detail messages of the Error.
+func (self Error) stringDetails() []string {
+	codec := *_detailCodec.Load()
+
+	details := make([]string, 0, len(self.details))
+	for _, message := range self.details {
+		details = append(details, codec.String(message))
+	}
+
+	return details
+}
+
+// encodeDetails encodes the detail messages with the DetailCodec, skipping the
+// ones it cannot encode.
+func encodeDetails(details []detail) [][]byte {
+	codec := *_detailCodec.Load()
+
+	var encoded [][]byte
+
+	for _, message := range details {
+		data, err := codec.Encode(message)
+		if err != nil {
+			continue
+		}
+
+		encoded = append(encoded, data)
+	}
+
+	return encoded
+}
+
+// decodeDetails restores the detail messages encoded by encodeDetails.
+func decodeDetails(encoded [][]byte) []detail {
+	codec := *_detailCodec.Load()
+
+	var details []detail
+
+	for _, data := range encoded {
+		message, err := codec.Decode(data)
+		if err != nil {
+			continue
+		}
+
+		details = append(details, message)
+	}
+
+	return details
+}
+
+// jsonDetails encodes the detail messages with the DetailCodec, skipping the ones
+// it cannot encode.
+func jsonDetails(details []detail) []json.RawMessage {
+	codec := *_detailCodec.Load()
+
+	var encoded []json.RawMessage
+
+	for _, message := range details {
+		data, err := codec.EncodeJSON(message)
+		if err != nil {
+			continue
+		}
+
+		encoded = append(encoded, data)
+	}
+
+	return encoded
+}
@@ -0,0 +1,101 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrQuotaExceeded = errors.New("quota of %s exceeded")
+
+type quotaDetail struct {
+	Resource string `json:"resource"`
+	Limit    int    `json:"limit"`
+}
+
+type quotaCodec struct{}
+
+func (self quotaCodec) String(message any) string {
+	detail := message.(quotaDetail)
+	return "Quota: " + detail.Resource
+}
+
+func (self quotaCodec) Encode(message any) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+func (self quotaCodec) Decode(data []byte) (any, error) {
+	var detail quotaDetail
+	err := json.Unmarshal(data, &detail)
+
+	return detail, err
+}
+
+func (self quotaCodec) EncodeJSON(message any) ([]byte, error) {
+	return json.Marshal(message)
+}
+
+func TestDetail(t *testing.T) {
+	t.Parallel()
+
+	err := ErrQuotaExceeded.Raise("requests").
+		Detail(quotaDetail{Resource: "requests", Limit: 100}).
+		Detail(nil).
+		Cause(ErrUserNotFound.Raise("Alex").Detail(quotaDetail{Resource: "users", Limit: 5}))
+
+	if len(err.Details()) != 1 || len(err.AllDetails()) != 2 {
+		t.FailNow()
+	}
+
+	if detail, ok := err.AllDetails()[1].(quotaDetail); !ok || detail.Resource != "users" {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.Report(errors.ReportOptions{All: true}), "    Details:\n      {requests 100}\n") {
+		t.FailNow()
+	}
+
+	verbose, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if jerr != nil || !strings.Contains(string(verbose), `"details":[{"resource":"requests","limit":100}]`) {
+		t.FailNow()
+	}
+
+	// The default codec cannot restore detail messages of unknown types
+	data, merr := err.MarshalBinary()
+	if merr != nil {
+		t.FailNow()
+	}
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || len(decoded.AllDetails()) != 0 {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestSetDetailCodec(t *testing.T) {
+	errors.SetDetailCodec(quotaCodec{})
+	defer errors.SetDetailCodec(nil)
+
+	err := ErrQuotaExceeded.Raise("requests").Detail(quotaDetail{Resource: "requests", Limit: 100})
+
+	if !strings.Contains(err.Report(errors.ReportOptions{}), "    Details:\n      Quota: requests\n") {
+		t.FailNow()
+	}
+
+	data, merr := err.MarshalBinary()
+	if merr != nil {
+		t.FailNow()
+	}
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || len(decoded.Details()) != 1 {
+		t.FailNow()
+	}
+
+	if detail, ok := decoded.Details()[0].(quotaDetail); !ok || detail.Limit != 100 {
+		t.FailNow()
+	}
+}
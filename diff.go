@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const _DIFF_FRAMES = 3
+
+type diffable struct {
+	kind     string
+	module   string
+	message  string
+	extra    map[string]any
+	frames   []Frame
+	template bool
+}
+
+func toDiffable(err error) diffable {
+	var rerr Error
+
+	switch cerr := err.(type) {
+	case nil:
+		return diffable{kind: "<nil>"}
+	case Error:
+		rerr = cerr
+	case *Error:
+		if cerr == nil {
+			return diffable{kind: "<nil>"}
+		}
+
+		rerr = *cerr
+	case target:
+		return diffable{kind: cerr.template.kind, module: cerr.template.module, template: true}
+	default:
+		return diffable{
+			kind:    strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+			message: err.Error(),
+		}
+	}
+
+	return diffable{
+		kind:    rerr.kind,
+		module:  rerr.module,
+		message: rerr.String(),
+		extra:   rerr.extra,
+		frames:  rerr.stackTrace[:min(len(rerr.stackTrace), _DIFF_FRAMES)],
+	}
+}
+
+func diffFrame(frame Frame) string {
+	return frame.Function + " (" + frame.File + ":" + strconv.Itoa(frame.Line) + ")"
+}
+
+// Diff returns a human-readable difference between the type, package, message,
+// extra and innermost frames of two errors, one line per difference, or an empty
+// string if they don't differ, such as to explain why a test assertion failed.
+// Only the types and packages are compared against the Target of a template.
+func Diff(a error, b error) string {
+	diffA := toDiffable(a)
+	diffB := toDiffable(b)
+
+	diff := ""
+	line := func(field string, valueA string, valueB string) {
+		if valueA != valueB {
+			diff += field + ": " + valueA + " != " + valueB + "\n"
+		}
+	}
+
+	line("kind", strconv.Quote(diffA.kind), strconv.Quote(diffB.kind))
+	line("module", strconv.Quote(diffA.module), strconv.Quote(diffB.module))
+
+	if diffA.template || diffB.template {
+		return diff
+	}
+	line("message", strconv.Quote(diffA.message), strconv.Quote(diffB.message))
+
+	keys := make([]string, 0, len(diffA.extra)+len(diffB.extra))
+	for key := range diffA.extra {
+		keys = append(keys, key)
+	}
+
+	for key := range diffB.extra {
+		if _, ok := diffA.extra[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		valueA, valueB := "<missing>", "<missing>"
+		if value, ok := diffA.extra[key]; ok {
+			valueA = fmt.Sprintf("%#v", value)
+		}
+
+		if value, ok := diffB.extra[key]; ok {
+			valueB = fmt.Sprintf("%#v", value)
+		}
+
+		line("extra["+key+"]", valueA, valueB)
+	}
+
+	for i := 0; i < max(len(diffA.frames), len(diffB.frames)); i++ {
+		frameA, frameB := "<missing>", "<missing>"
+		if i < len(diffA.frames) {
+			frameA = diffFrame(diffA.frames[i])
+		}
+
+		if i < len(diffB.frames) {
+			frameB = diffFrame(diffB.frames[i])
+		}
+
+		line("frame["+strconv.Itoa(i)+"]", frameA, frameB)
+	}
+
+	return diff
+}
@@ -0,0 +1,46 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	a := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 1})
+	b := ErrUserNotFound.Raise("Bob").Extra(map[string]any{"userID": 1, "retry": true})
+
+	diff := errors.Diff(a, b)
+	if !strings.Contains(diff, `message: "user Alex not found" != "user Bob not found"`) {
+		t.FailNow()
+	}
+
+	if !strings.Contains(diff, "extra[retry]: <missing> != true") || strings.Contains(diff, "kind:") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(diff, "frame[0]: ") || strings.Contains(diff, "extra[userID]") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(errors.Diff(a, ErrOtherLibrary), `kind: "user %s not found" != "errors.errorString"`) {
+		t.FailNow()
+	}
+
+	if errors.Diff(a, ErrUserNotFound.Target()) != "" {
+		t.FailNow()
+	}
+
+	if errors.Diff(a, ErrCannotDeposit.Target()) != `kind: "user %s not found" != "cannot deposit"`+"\n" {
+		t.FailNow()
+	}
+
+	if errors.Diff(ErrOtherLibrary, ErrOtherLibrary) != "" {
+		t.FailNow()
+	}
+}
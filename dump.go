@@ -0,0 +1,21 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+var _goroutineDump = &atomic.Bool{}
+
+// SetGoroutineDump sets whether to capture the stack traces of all goroutines
+// when Errors with the fatal level are raised (default is false), since
+// deadlock-style failures need visibility beyond the raising goroutine.
+func SetGoroutineDump(enabled bool) {
+	_goroutineDump.Store(enabled)
+}
+
+// GoroutineDump returns the stack traces of all goroutines captured when the
+// Error was raised with the fatal level, if enabled with SetGoroutineDump. It is
+// attached to the Sentry reports as goroutines.txt.
+func (self Error) GoroutineDump() string {
+	return string(self.goroutineDump)
+}
@@ -0,0 +1,32 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrDeadlock = errors.NewWithOptions("deadlock detected", errors.NewOptions{Level: errors.LevelFatal})
+
+// nolint:paralleltest
+func TestGoroutineDump(t *testing.T) {
+	if ErrDeadlock.Raise().GoroutineDump() != "" {
+		t.FailNow()
+	}
+
+	errors.SetGoroutineDump(true)
+	defer errors.SetGoroutineDump(false)
+
+	err := ErrDeadlock.Raise()
+	if !strings.Contains(err.GoroutineDump(), "goroutine ") || ErrCannotDeposit.Raise().GoroutineDump() != "" {
+		t.FailNow()
+	}
+
+	attachments := err.SentryReport().Attachments
+	if len(attachments) != 1 || attachments[0].Filename != "goroutines.txt" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"runtime"
+	"strconv"
+)
+
+// Errorf raises a new Error formatting its message as fmt.Errorf does, wrapping
+// the errors of the %w verbs as its causes and capturing the stack trace, as a
+// drop-in replacement of fmt.Errorf. Its type is the format itself.
+func Errorf(format string, args ...any) *Error {
+	wrapped := fmt.Errorf(format, args...)
+
+	// Raised Errors format their report with %v, so their plain message is used.
+	plainArgs := make([]any, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case Error:
+			plainArgs[i] = goerrors.New(arg.String())
+		case *Error:
+			if arg != nil {
+				plainArgs[i] = goerrors.New(arg.String())
+			}
+		default:
+			plainArgs[i] = arg
+		}
+	}
+
+	template := Error{
+		kind:              format,
+		module:            "unknown",
+		message:           format,
+		captureStackTrace: true,
+	}
+
+	stackFrames := make([]uintptr, 1)
+
+	length := callers(2, stackFrames)
+	if length > 0 {
+		frame, _ := runtime.CallersFrames(stackFrames[:length]).Next()
+
+		if resolved := (*_moduleResolver.Load())(frame); resolved != "" {
+			template.module = resolved
+		}
+
+		template.declaredAt = frame.File + ":" + strconv.Itoa(frame.Line)
+	}
+
+	err := template.raise(3, fmt.Errorf(format, plainArgs...).Error())
+
+	switch wrapped := wrapped.(type) {
+	case interface{ Unwrap() error }:
+		err.cause = wrapped.Unwrap()
+		err.inlineCause = true
+	case interface{ Unwrap() []error }:
+		err.cause = goerrors.Join(wrapped.Unwrap()...)
+		err.inlineCause = true
+	}
+
+	return err
+}
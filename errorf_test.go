@@ -0,0 +1,43 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestErrorf(t *testing.T) {
+	t.Parallel()
+
+	err := errors.Errorf("cannot load user %d: %w", 42, ErrOtherLibrary)
+	if err.Error() != "cannot load user 42: other library error" {
+		t.FailNow()
+	}
+
+	if !goerrors.Is(err, ErrOtherLibrary) || !strings.HasSuffix(err.StackTrace()[0].Function, ".TestErrorf") {
+		t.FailNow()
+	}
+
+	if err.Module() != "github.com/neoxelox/errors_test" {
+		t.FailNow()
+	}
+
+	raised := ErrUserNotFound.Raise("Alex")
+
+	err = errors.Errorf("sync failed (%w, %w)", raised, ErrOtherLibrary)
+	if err.Error() != "sync failed (user Alex not found, other library error)" {
+		t.FailNow()
+	}
+
+	if !goerrors.Is(err, ErrOtherLibrary) || !goerrors.Is(err, ErrUserNotFound.Target()) {
+		t.FailNow()
+	}
+
+	if errors.Errorf("no causes").Unwrap() != nil {
+		t.FailNow()
+	}
+}
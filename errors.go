@@ -2,115 +2,580 @@
 package errors
 
 import (
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"maps"
+	"os"
 	"reflect"
 	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
-
-	"github.com/getsentry/sentry-go"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const _MAX_FRAMES = 100
 
-var _ANSI_COLOR_PATTERN = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+const _MAX_ATTACHMENT_SIZE = 1 << 20
+
+const _MAX_SUMMARY_LENGTH = 256
+
+const _MAX_BREADCRUMBS = 32
+
+const (
+	_COLOR_RED        = "\x1b[0;31m"
+	_COLOR_BRIGHT_RED = "\x1b[1;91m"
+	_COLOR_BOLD_RED   = "\x1b[1;31m"
+	_COLOR_BLUE       = "\x1b[0;34m"
+	_COLOR_MAGENTA    = "\x1b[0;35m"
+	_COLOR_BOLD       = "\x1b[1m"
+	_COLOR_DIM        = "\x1b[2m"
+	_COLOR_RESET      = "\x1b[0m"
+)
+
+var _stackCapture = func() *atomic.Bool {
+	stackCapture := &atomic.Bool{}
+	noStack, _ := strconv.ParseBool(os.Getenv("ERRORS_NO_STACK"))
+	stackCapture.Store(!noStack)
+
+	return stackCapture
+}()
+
+// SetStackCapture enables or disables the stack trace capture process-wide,
+// regardless of the templates' setting (default is enabled, unless the
+// ERRORS_NO_STACK environment variable is set to true).
+func SetStackCapture(enabled bool) {
+	_stackCapture.Store(enabled)
+}
+
+var _codePrefix = func() *atomic.Bool {
+	codePrefix := &atomic.Bool{}
+	enabled, _ := strconv.ParseBool(os.Getenv("ERRORS_CODE_PREFIX"))
+	codePrefix.Store(enabled)
+
+	return codePrefix
+}()
+
+// SetCodePrefix enables or disables prefixing the messages returned by Error and
+// String with the codes of the Errors, such as "[USER_NOT_FOUND] user Alex not
+// found", so systems that only see the flat messages, such as legacy log parsers
+// or external partners, can still classify them (default is disabled, unless the
+// ERRORS_CODE_PREFIX environment variable is set to true).
+func SetCodePrefix(enabled bool) {
+	_codePrefix.Store(enabled)
+}
+
+var _registry sync.Map
+
+// packagePath returns the package of a fully qualified function name, handling
+// methods (pkg.(*Type).Method), closures (pkg.Func.func1) and generics (pkg.Func[...]).
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+
+	return function[:slash+1+dot]
+}
+
+func defaultModuleResolver(frame runtime.Frame) string {
+	return packagePath(frame.Function)
+}
+
+var _moduleResolver = func() *atomic.Pointer[func(runtime.Frame) string] {
+	moduleResolver := &atomic.Pointer[func(runtime.Frame) string]{}
+	moduleResolver.Store(ptr(defaultModuleResolver))
+
+	return moduleResolver
+}()
+
+// SetModuleResolver sets the function used by New to detect the module of the
+// declared Errors from the frame where they are declared (default is the package).
+func SetModuleResolver(resolver func(runtime.Frame) string) {
+	if resolver == nil {
+		resolver = defaultModuleResolver
+	}
+
+	_moduleResolver.Store(&resolver)
+}
+
+var _frameAggregation = &atomic.Bool{}
+
+// SetFrameAggregation enables or disables collapsing the frames that are identical
+// across the errors of a chain into a repetition count in reports, such as when
+// an error is raised repeatedly within a loop (default is disabled).
+func SetFrameAggregation(enabled bool) {
+	_frameAggregation.Store(enabled)
+}
+
+func omittedFrames(omitted int) string {
+	return "[... " + strconv.Itoa(omitted) + " identical frames omitted]"
+}
+
+func colorize(text string, color string, colored bool) string {
+	if !colored || color == "" {
+		return text
+	}
+
+	return color + text + _COLOR_RESET
+}
+
+// Frame represents a single frame of an Error's stack trace.
+type Frame struct {
+	File     string
+	Line     int
+	Function string
+	Class    FrameClass
+}
+
+func defaultFrameFormatter(frame Frame) string {
+	return frame.File + ":" + strconv.Itoa(frame.Line) + "\n    " + frame.Function
+}
+
+var _frameFormatter = func() *atomic.Pointer[func(Frame) string] {
+	frameFormatter := &atomic.Pointer[func(Frame) string]{}
+	frameFormatter.Store(ptr(defaultFrameFormatter))
+
+	return frameFormatter
+}()
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// SetFrameFormatter sets the function used to render each frame of the stack traces
+// in string reports, such as to emit IDE-clickable links or relative paths (default
+// is the file:line followed by the function). Multiline outputs are indented.
+func SetFrameFormatter(formatter func(Frame) string) {
+	if formatter == nil {
+		formatter = defaultFrameFormatter
+	}
+
+	_frameFormatter.Store(&formatter)
+}
+
+func callersFrames(pcs []uintptr) []Frame {
+	stackTrace := make([]Frame, 0, len(pcs))
+
+	cframes := runtime.CallersFrames(pcs)
+	for {
+		cframe, more := cframes.Next()
+		stackTrace = append(stackTrace, Frame{
+			File:     cframe.File,
+			Line:     cframe.Line,
+			Function: cframe.Function,
+			Class:    classifyFrame(cframe.Function),
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return stackTrace
+}
+
+// foreignStackTrace extracts the innermost stack trace carried by errors from
+// libraries such as github.com/pkg/errors or github.com/cockroachdb/errors,
+// which expose a StackTrace method returning a slice of program counters.
+func foreignStackTrace(err error) []Frame {
+	var stackTrace []Frame
+
+	for err != nil {
+		method := reflect.ValueOf(err).MethodByName("StackTrace")
+		if method.IsValid() && method.Type().NumIn() == 0 && method.Type().NumOut() == 1 {
+			result := method.Call(nil)[0]
+			if result.Kind() == reflect.Slice && result.Type().Elem().Kind() == reflect.Uintptr && result.Len() > 0 {
+				pcs := make([]uintptr, result.Len())
+				for i := range pcs {
+					pcs[i] = uintptr(result.Index(i).Uint())
+				}
+
+				stackTrace = callersFrames(pcs)
+			}
+		}
 
-type frame struct {
-	file     string
-	line     int
-	function string
+		switch wrapper := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapper.Unwrap()
+		case interface{ Cause() error }:
+			err = wrapper.Cause()
+		default:
+			err = nil
+		}
+	}
+
+	return stackTrace
 }
 
-// Error represents an error with traceback and additional info.
+type breadcrumb struct {
+	timestamp time.Time
+	message   string
+	data      map[string]any
+}
+
+type attachment struct {
+	name        string
+	contentType string
+	data        []byte
+}
+
+// Level represents the severity of an Error.
+type Level int
+
+const (
+	// LevelWarning is the severity of non-fatal issues.
+	LevelWarning Level = iota - 1
+	// LevelError is the default severity.
+	LevelError
+	// LevelFatal is the severity of unrecoverable failures.
+	LevelFatal
+)
+
+// String implements the Stringer interface.
+func (self Level) String() string {
+	switch self {
+	case LevelWarning:
+		return "warning"
+	case LevelFatal:
+		return "fatal"
+	default:
+		return "error"
+	}
+}
+
+// Error represents an error with traceback and additional info. Its read methods,
+// such as Error, String and StringReport, use value receivers so Error values
+// implement error and can be the targets of the standard library's errors.As,
+// thus calling them on a nil *Error panics as with any value method. The fmt
+// verbs print nil *Errors as <nil>, and OrNil and IsNil guard against the
+// typed-nil error interfaces instead.
 type Error struct {
 	kind              string
 	module            string
+	code              string
+	declaredAt        string
 	message           string
+	args              []any
+	level             Level
 	cause             error
 	extra             map[string]any
-	stackTrace        []frame
+	stackTrace        []Frame
+	observedAt        []Frame
 	captureStackTrace bool
 	tags              map[string]string
+	attachments       []attachment
+	breadcrumbs       []breadcrumb
+	payload           any
+	inlineCause       bool
+	sentryEventID     string
+	op                string
+	goroutineDump     []byte
+	request           *httpRequest
+	raisedAt          time.Time
+	key               string
+	query             *query
+	details           []detail
+	memStats          *MemStats
+	docs              string
+	escalated         bool
+	limited           bool
+	suppressed        int
+	required          []string
+}
+
+// NewOptions represents the options to declare an Error.
+type NewOptions struct {
+	// DisableStack skips capturing the stack trace when the Error is raised.
+	DisableStack bool
+	// Code is a stable textual identifier of the Error.
+	Code string
+	// Module overrides the package detected from where the Error is declared,
+	// which can't be detected in headless mode.
+	Module string
+	// Level is the severity of the Error (default is error).
+	Level Level
+	// Docs is the URL of the documentation or runbook of the Error.
+	Docs string
 }
 
-// New creates a new Error with a message (can have a format) and
+// New creates a new Error template with a message (can have a format) and
 // sets to optionally capture the stack trace when raised (default is true).
-func New(message string, captureStackTrace ...bool) Error {
+func New(message string, captureStackTrace ...bool) Template {
+	_captureStackTrace := true
+	if len(captureStackTrace) > 0 {
+		_captureStackTrace = captureStackTrace[0]
+	}
+
+	return declare(3, message, NewOptions{DisableStack: !_captureStackTrace})
+}
+
+// NewWarning creates a new Error template like New but with the warning level,
+// for non-fatal issues that must be reported without counting as errors.
+func NewWarning(message string, captureStackTrace ...bool) Template {
 	_captureStackTrace := true
 	if len(captureStackTrace) > 0 {
 		_captureStackTrace = captureStackTrace[0]
 	}
 
-	module := "unknown"
+	return declare(3, message, NewOptions{DisableStack: !_captureStackTrace, Level: LevelWarning})
+}
+
+// NewWithOptions creates a new Error template with a message (can have a format)
+// and options.
+func NewWithOptions(message string, options NewOptions) Template {
+	return declare(3, message, options)
+}
+
+func declare(skip int, message string, options NewOptions) Template {
+	module := ""
+	declaredAt := ""
 	stackFrames := make([]uintptr, 1)
 
-	length := runtime.Callers(2, stackFrames)
+	length := callers(skip, stackFrames)
 	if length > 0 {
 		frame, _ := runtime.CallersFrames(stackFrames[:length]).Next()
 
-		separator := strings.LastIndex(frame.Function, ".")
-		if separator >= 0 {
-			module = frame.Function[:separator]
+		if resolved := (*_moduleResolver.Load())(frame); resolved != "" {
+			module = resolved
 		}
+
+		declaredAt = frame.File + ":" + strconv.Itoa(frame.Line)
+	}
+
+	if options.Module != "" {
+		module = options.Module
+	}
+
+	if module == "" {
+		module = unknownModule()
 	}
 
-	return Error{
+	template := Template{template: &Error{
 		kind:              message,
 		module:            module,
+		code:              options.Code,
+		declaredAt:        declaredAt,
 		message:           message,
+		level:             options.Level,
 		cause:             nil,
 		extra:             nil,
 		stackTrace:        nil,
-		captureStackTrace: _captureStackTrace,
+		captureStackTrace: !options.DisableStack,
 		tags:              nil,
+		key:               module + "." + message,
+		docs:              options.Docs,
+	}}
+
+	_registry.Store(kindKey(template.template), template)
+
+	return template
+}
+
+// Lookup returns the Error template declared in a package with a type, if any,
+// such as the template of an error received from another service.
+func Lookup(module string, kind string) (Template, bool) {
+	template, ok := _registry.Load(module + "." + kind)
+	if !ok {
+		return Template{}, false
 	}
+
+	return template.(Template), true
 }
 
-// Raise creates a new Error instance formatting its message if
-// needed and optionally captures its stack trace.
+// Raise creates a new Error instance of the same type as the Error formatting
+// its message if needed and optionally captures its stack trace. Raising an
+// already raised Error, a common misuse when returning it again, preserves its
+// message, stack trace, extra... and adds the call site to where it was
+// observed, as Rewrap does when stack traces are captured, warning about it if
+// enabled with SetDoubleRaiseWarning.
 func (self Error) Raise(args ...any) *Error {
-	var stackTrace []frame
+	if !self.raisedAt.IsZero() {
+		return self.reraise(3)
+	}
+
+	return self.raise(3, sprintf(self.message, args))
+}
+
+var _doubleRaiseWarning atomic.Bool
+
+// SetDoubleRaiseWarning enables or disables logging a warning with the default
+// slog logger when Raise is called on an already raised Error, such as during
+// development (default is disabled).
+func SetDoubleRaiseWarning(enabled bool) {
+	_doubleRaiseWarning.Store(enabled)
+}
+
+// reraise returns a copy of the raised Error observed at the call site. The call
+// site is added as the most recent call of where it was already observed, and
+// only when the stack traces of the Error are captured.
+func (self Error) reraise(skip int) *Error {
+	err := self
+	err.extra = maps.Clone(self.extra)
+	err.tags = maps.Clone(self.tags)
+	err.details = append([]detail(nil), self.details...)
+
+	capture := self.captureStackTrace && _stackCapture.Load()
 
-	if self.captureStackTrace {
+	stackFrames := make([]uintptr, 1)
+	if capture && len(self.observedAt) == 0 {
+		stackFrames = make([]uintptr, _MAX_FRAMES)
+	}
+
+	var callSite []Frame
+
+	length := callers(skip, stackFrames)
+	if length > 0 {
+		callSite = callersFrames(stackFrames[:length])
+	}
+
+	if capture && len(callSite) > 0 {
+		err.observedAt = append(callSite, self.observedAt...)
+	}
+
+	if _doubleRaiseWarning.Load() {
+		at := "unknown"
+		if len(callSite) > 0 {
+			at = callSite[0].File + ":" + strconv.Itoa(callSite[0].Line)
+		}
+
+		slog.Warn("errors: Raise called on an already raised Error", "kind", err.kind, "module", err.module, "at", at)
+	}
+
+	return &err
+}
+
+// sprintf formats the message with the arguments only when needed, so raising
+// Errors with constant messages does not allocate it.
+func sprintf(message string, args []any) string {
+	if len(args) == 0 && strings.IndexByte(message, '%') < 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+func (self Error) formatted() string {
+	if self.args == nil {
+		return self.message
+	}
+
+	return fmt.Sprintf(self.message, self.args...)
+}
+
+func (self Error) raise(skip int, message string) *Error {
+	var stackTrace []Frame
+
+	if self.captureStackTrace && _stackCapture.Load() {
 		stackFrames := make([]uintptr, _MAX_FRAMES)
 
-		length := runtime.Callers(2, stackFrames)
+		length := callers(skip, stackFrames)
 		if length > 0 {
-			stackTrace = make([]frame, 0, length)
-
-			cframes := runtime.CallersFrames(stackFrames[:length])
-			for {
-				cframe, more := cframes.Next()
-				stackTrace = append(stackTrace, frame{
-					file:     cframe.File,
-					line:     cframe.Line,
-					function: cframe.Function,
-				})
-
-				if !more {
-					break
-				}
-			}
+			stackTrace = callersFrames(stackFrames[:length])
 		}
 	}
 
-	return &Error{
+	err := &Error{
 		kind:              self.kind,
 		module:            self.module,
-		message:           fmt.Sprintf(self.message, args...),
+		code:              self.code,
+		declaredAt:        self.declaredAt,
+		message:           message,
+		level:             self.level,
 		cause:             nil,
-		extra:             make(map[string]any),
+		extra:             nil,
 		stackTrace:        stackTrace,
 		captureStackTrace: self.captureStackTrace,
-		tags:              make(map[string]string),
+		tags:              nil,
+		raisedAt:          time.Now(),
+		key:               self.key,
+		docs:              self.docs,
+		required:          self.required,
+	}
+
+	if self.level == LevelFatal && _goroutineDump.Load() {
+		err.goroutineDump = goroutineDump()
+	}
+
+	// The hooks are checked before calling them so raising pays nothing for the
+	// ones that are not enabled
+	if self.tags != nil || _processTags.Load() != nil {
+		err.tags = withProcessTags(self.tags)
+	}
+
+	if _memStatsKinds.Load() != nil {
+		err.memStats = memStats(err)
+	}
+
+	if _statsEnabled.Load() {
+		_stats.record(err)
 	}
+
+	if _subscriptions.list.Load() != nil {
+		publish(err)
+	}
+
+	return err
 }
 
 // Skip removes n frames of the raised Error.
 func (self *Error) Skip(frames int) *Error {
-	if self.captureStackTrace {
-		self.stackTrace = self.stackTrace[frames:]
+	if frames > len(self.stackTrace) {
+		frames = len(self.stackTrace)
+	}
+
+	self.stackTrace = self.stackTrace[frames:]
+
+	return self
+}
+
+// SkipWhile removes the innermost frames of the raised Error while the predicate
+// holds, so wrapper helpers can remove themselves regardless of inlining.
+func (self *Error) SkipWhile(predicate func(Frame) bool) *Error {
+	frames := 0
+	for frames < len(self.stackTrace) && predicate(self.stackTrace[frames]) {
+		frames++
+	}
+
+	self.stackTrace = self.stackTrace[frames:]
+
+	return self
+}
+
+// SkipFunc removes the innermost frames of the raised Error whose function
+// matches the regular expression pattern, such as "^myapp/httputil\\.". Invalid
+// patterns remove no frames.
+func (self *Error) SkipFunc(pattern string) *Error {
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return self
+	}
+
+	return self.SkipWhile(func(frame Frame) bool {
+		return matcher.MatchString(frame.Function)
+	})
+}
+
+// Rewrap captures a second stack trace of the raised Error where it crosses an
+// asynchronous boundary, such as a worker pulling it from a queue, shown in the
+// reports along with the stack trace where it was raised.
+func (self *Error) Rewrap() *Error {
+	if !self.captureStackTrace || !_stackCapture.Load() {
+		return self
+	}
+
+	stackFrames := make([]uintptr, _MAX_FRAMES)
+
+	length := callers(2, stackFrames)
+	if length > 0 {
+		self.observedAt = callersFrames(stackFrames[:length])
 	}
 
 	return self
@@ -118,67 +583,469 @@ func (self *Error) Skip(frames int) *Error {
 
 // With adds more context to the raised Error's message.
 func (self *Error) With(message string, args ...any) *Error {
-	self.message += ": " + fmt.Sprintf(message, args...)
+	self.message = self.formatted() + ": " + fmt.Sprintf(message, args...)
+	self.args = nil
 
 	return self
 }
 
-// Extra adds extra information to the raised Error.
+// Extra adds extra information to the raised Error, redacted as per the rules
+// set with SetRedaction.
 func (self *Error) Extra(extra map[string]any) *Error {
-	for key, value := range extra {
+	if self.extra == nil {
+		self.extra = make(map[string]any, len(extra))
+	}
+
+	for key, value := range redactExtra(extra) {
 		self.extra[key] = value
 	}
 
 	return self
 }
 
-// Cause wraps an error into the raised Error.
+// GetExtraAs returns the extra information stored under key in the first Error of
+// the chain that has it, asserted to type T, and whether it was found.
+func GetExtraAs[T any](err error, key string) (T, bool) {
+	for err != nil {
+		var extra map[string]any
+
+		switch cerr := err.(type) {
+		case Error:
+			extra, err = cerr.extra, cerr.cause
+		case *Error:
+			extra, err = cerr.extra, cerr.cause
+		default:
+			err = nil
+		}
+
+		if value, ok := extra[key]; ok {
+			typed, ok := value.(T)
+
+			return typed, ok
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// Cause wraps an error into the raised Error. Errors from external libraries
+// are translated as registered with MapExternal.
 func (self *Error) Cause(err error) *Error {
+	err = mapExternal(err, 1)
+
+	if chainContains(err, self) {
+		err = ErrChainCycle.Raise()
+	}
+
 	self.cause = err
 
 	return self
 }
 
-// Tags adds tags to the raised Error to further classify
-// errors in services such as Sentry or New Relic.
+// Attach adds a file (request bodies, SQL statements, config dumps...) to the raised
+// Error to be sent along with it in services such as Sentry and in the verbose
+// JSON encoding. The data is redacted as per the rules set with SetRedaction and
+// truncated to 1MiB.
+func (self *Error) Attach(name string, contentType string, data []byte) *Error {
+	data = redactAttachment(name, contentType, data)
+
+	if len(data) > _MAX_ATTACHMENT_SIZE {
+		data = data[:_MAX_ATTACHMENT_SIZE]
+	}
+
+	self.attachments = append(self.attachments, attachment{
+		name:        name,
+		contentType: contentType,
+		data:        append([]byte(nil), data...),
+	})
+
+	return self
+}
+
+// Op records the logical operation (such as "usecase.Deposit") of the layer
+// raising the Error, independent of function names which change under refactors.
+func (self *Error) Op(name string) *Error {
+	self.op = name
+
+	return self
+}
+
+// Breadcrumb records a step taken by the raised Error as it travels up the
+// layers, keeping the last 32 breadcrumbs, which are shown chronologically in the
+// reports and sent as breadcrumbs to services such as Sentry.
+func (self *Error) Breadcrumb(message string, data map[string]any) *Error {
+	if len(self.breadcrumbs) >= _MAX_BREADCRUMBS {
+		self.breadcrumbs = append(self.breadcrumbs[:0], self.breadcrumbs[1:]...)
+	}
+
+	self.breadcrumbs = append(self.breadcrumbs, breadcrumb{
+		timestamp: time.Now(),
+		message:   message,
+		data:      redactExtra(data),
+	})
+
+	return self
+}
+
+// AsWarning sets the raised Error's level to warning.
+func (self *Error) AsWarning() *Error {
+	self.level = LevelWarning
+
+	return self
+}
+
+// Tags adds tags to the raised Error to further classify errors in services such
+// as Sentry or New Relic, redacted as per the rules set with SetRedaction.
 func (self *Error) Tags(tags map[string]any) *Error {
+	if self.tags == nil {
+		self.tags = make(map[string]string, len(tags))
+	}
+
 	for key, value := range tags {
-		self.tags[key] = fmt.Sprintf("%v", value)
+		self.tags[key] = redactTag(key, fmt.Sprintf("%v", value))
 	}
 
 	return self
 }
 
-// Is compares whether an error is Error's type.
+// Level returns the Error's severity.
+func (self Error) Level() Level {
+	return self.level
+}
+
+// Message returns the raised Error's message without the wrapped errors' ones.
+func (self Error) Message() string {
+	return self.formatted()
+}
+
+// Kind returns the Error's type, that is, the message it was declared with.
+func (self Error) Kind() string {
+	return self.kind
+}
+
+// Code returns the Error's stable textual identifier, if any.
+func (self Error) Code() string {
+	return self.code
+}
+
+// DocsURL returns the URL of the Error's documentation or runbook, if any.
+func (self Error) DocsURL() string {
+	return self.docs
+}
+
+// Module returns the package where the Error was declared.
+func (self Error) Module() string {
+	return self.module
+}
+
+// Extras returns a copy of the extra information of the raised Error.
+func (self Error) Extras() map[string]any {
+	extra := make(map[string]any, len(self.extra))
+	for key, value := range self.extra {
+		extra[key] = value
+	}
+
+	return extra
+}
+
+// GetTags returns a copy of the tags of the raised Error.
+func (self Error) GetTags() map[string]string {
+	tags := make(map[string]string, len(self.tags))
+	for key, value := range self.tags {
+		tags[key] = value
+	}
+
+	return tags
+}
+
+// chain returns the Errors of the chain, starting from the Error itself.
+func (self Error) chain() []Error {
+	chain := []Error{self}
+
+	for cause := self.cause; cause != nil && len(chain) < maxChainDepth(); {
+		switch err := cause.(type) {
+		case Error:
+			chain = append(chain, err)
+			cause = err.cause
+		case *Error:
+			chain = append(chain, *err)
+			cause = err.cause
+		default:
+			cause = nil
+		}
+	}
+
+	return chain
+}
+
+// AllExtras returns the extra information of all errors wrapped within the Error
+// itself merged, the outermost errors winning, and optionally prefixes the keys
+// with the depth of their error in the chain, such as "0.userID" or "1.userID",
+// to keep them all (default is false).
+func (self Error) AllExtras(prefixed ...bool) map[string]any {
+	_prefixed := false
+	if len(prefixed) > 0 {
+		_prefixed = prefixed[0]
+	}
+
+	extras := make(map[string]any)
+	for depth, err := range self.chain() {
+		for key, value := range err.extra {
+			if _prefixed {
+				key = strconv.Itoa(depth) + "." + key
+			}
+
+			if _, ok := extras[key]; !ok {
+				extras[key] = value
+			}
+		}
+	}
+
+	return extras
+}
+
+// AllTags returns the tags of all errors wrapped within the Error itself merged,
+// the outermost errors winning, and optionally prefixes the keys with the depth
+// of their error in the chain (default is false).
+func (self Error) AllTags(prefixed ...bool) map[string]string {
+	_prefixed := false
+	if len(prefixed) > 0 {
+		_prefixed = prefixed[0]
+	}
+
+	tags := make(map[string]string)
+	for depth, err := range self.chain() {
+		for key, value := range err.tags {
+			if _prefixed {
+				key = strconv.Itoa(depth) + "." + key
+			}
+
+			if _, ok := tags[key]; !ok {
+				tags[key] = value
+			}
+		}
+	}
+
+	return tags
+}
+
+// StackTrace returns a copy of the raised Error's stack trace, starting from
+// the most recent call, optionally keeping only the frames matching all the
+// filters, such as OnlyInApp for compact logs.
+func (self Error) StackTrace(filters ...func(Frame) bool) []Frame {
+	if len(filters) == 0 {
+		return append([]Frame(nil), self.stackTrace...)
+	}
+
+	stackTrace := make([]Frame, 0, len(self.stackTrace))
+
+frames:
+	for _, frame := range self.stackTrace {
+		for _, filter := range filters {
+			if !filter(frame) {
+				continue frames
+			}
+		}
+
+		stackTrace = append(stackTrace, frame)
+	}
+
+	return stackTrace
+}
+
+// Unwrap returns the error wrapped into the Error, if any.
+func (self Error) Unwrap() error {
+	return self.cause
+}
+
+// Hash returns a stable identifier of the Error and all errors wrapped within
+// itself, based on their types and packages, to group the same failures
+// regardless of their messages, stack traces or extra information.
+func (self Error) Hash() string {
+	hash := fnv.New64a()
+
+	var cause error = self
+	for depth := 0; cause != nil && depth < maxChainDepth(); depth++ {
+		switch err := cause.(type) {
+		case Error:
+			hash.Write([]byte(kindKey(&err) + "\n"))
+			cause = err.cause
+		case *Error:
+			hash.Write([]byte(kindKey(err) + "\n"))
+			cause = err.cause
+		default:
+			hash.Write([]byte(reflect.TypeOf(err).String() + "\n"))
+			cause = nil
+		}
+	}
+
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// OpPath returns the operations recorded with Op by the errors wrapped within
+// the Error, from the outermost to the innermost, separated by arrows, such as
+// "view → usecase → repository".
+func (self Error) OpPath() string {
+	ops := make([]string, 0)
+
+	var cause error = self
+	for depth := 0; cause != nil && depth < maxChainDepth(); depth++ {
+		var op string
+
+		switch err := cause.(type) {
+		case Error:
+			op, cause = err.op, err.cause
+		case *Error:
+			op, cause = err.op, err.cause
+		default:
+			cause = nil
+		}
+
+		if op != "" {
+			ops = append(ops, op)
+		}
+	}
+
+	return strings.Join(ops, " → ")
+}
+
+// RaisedAt returns the time when the Error was raised.
+func (self Error) RaisedAt() time.Time {
+	return self.raisedAt
+}
+
+// SinceCause returns the time elapsed between the raising of the Error wrapped
+// within the Error itself and the raising of the Error itself, or 0 if the cause
+// is not an Error.
+func (self Error) SinceCause() time.Duration {
+	switch cause := self.cause.(type) {
+	case Error:
+		return self.raisedAt.Sub(cause.raisedAt)
+	case *Error:
+		return self.raisedAt.Sub(cause.raisedAt)
+	default:
+		return 0
+	}
+}
+
+// DeclaredAt returns the file:line where the Error was declared with New.
+func (self Error) DeclaredAt() string {
+	return self.declaredAt
+}
+
+// kindKey returns the key of the Error's type and package, precomputed by the
+// templates so raising does not allocate it.
+func kindKey(err *Error) string {
+	if err.key != "" {
+		return err.key
+	}
+
+	return err.module + "." + err.kind
+}
+
+func origin(err error) (Frame, bool) {
+	var stackTrace []Frame
+
+	switch cerr := err.(type) {
+	case nil:
+		return Frame{}, false
+	case Error:
+		stackTrace = cerr.stackTrace
+	case *Error:
+		stackTrace = cerr.stackTrace
+	default:
+		stackTrace = foreignStackTrace(cerr)
+	}
+
+	for _, sframe := range stackTrace {
+		if !strings.HasPrefix(sframe.Function, "runtime.") &&
+			!strings.HasPrefix(sframe.Function, "github.com/neoxelox/errors.") {
+			return sframe, true
+		}
+	}
+
+	return Frame{}, false
+}
+
+// SameOrigin checks whether two errors were raised at the same place, comparing the
+// innermost frame of their stack traces outside the runtime and this package.
+func SameOrigin(a error, b error) bool {
+	originA, ok := origin(a)
+	if !ok {
+		return false
+	}
+
+	originB, ok := origin(b)
+	if !ok {
+		return false
+	}
+
+	return originA == originB
+}
+
+// Is compares whether an error is Error's type. The comparison is symmetric, so
+// it can be called on the template (ErrUserNotFound.Is(err)) as well as through
+// the standard library on raised Errors (errors.Is(err, ErrUserNotFound.Target())),
+// which also walks their chain of causes.
 func (self Error) Is(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	switch other := err.(type) {
+	kind, module, ok := kindOf(err)
+
+	return ok && self.kind == kind && self.module == module
+}
+
+// kindOf returns the type and package of an Error or the target of a template.
+func kindOf(err error) (string, string, bool) {
+	switch typed := err.(type) {
 	case Error:
-		return self.kind == other.kind && self.module == other.module
+		return typed.kind, typed.module, true
 	case *Error:
-		return self.kind == other.kind && self.module == other.module
+		return typed.kind, typed.module, true
+	case target:
+		return typed.template.kind, typed.template.module, true
 	}
 
-	return false
+	return "", "", false
 }
 
-// Has checks whether an error is wrapped inside the Error itself.
+// Has checks whether an error is wrapped inside the Error itself, comparing the
+// Errors by type and the foreign errors as the standard library's errors.Is does
+// (by identity or their own Is methods), never by their messages.
 func (self Error) Has(err error) bool {
-	if self.Is(err) {
+	return self.has(err, 1)
+}
+
+func (self Error) has(err error, depth int) bool {
+	if err == nil {
+		return false
+	}
+
+	kind, module, ok := kindOf(err)
+	if ok && self.kind == kind && self.module == module {
 		return true
 	}
 
-	if self.cause != nil {
-		switch cause := self.cause.(type) {
+	for cause := self.cause; cause != nil && depth < maxChainDepth(); depth++ {
+		switch typed := cause.(type) {
 		case Error:
-			return cause.Has(err)
+			if ok && typed.kind == kind && typed.module == module {
+				return true
+			}
+
+			cause = typed.cause
 		case *Error:
-			return cause.Has(err)
+			if ok && typed.kind == kind && typed.module == module {
+				return true
+			}
+
+			cause = typed.cause
 		default:
-			return err == cause || err.Error() == cause.Error()
+			return goerrors.Is(cause, err)
 		}
 	}
 
@@ -187,24 +1054,106 @@ func (self Error) Has(err error) bool {
 
 // In checks whether the Error itself is wrapped inside an error.
 func (self Error) In(err error) bool {
-	switch err := err.(type) {
-	case Error:
-		return err.Has(self)
+	return inChain(err, self.kind, self.module, 1)
+}
+
+// inChain checks whether an Error with the type and package is wrapped inside an
+// error, comparing the Errors of the chain directly instead of boxing them into
+// errors, so the check doesn't allocate.
+func inChain(err error, kind string, module string, depth int) bool {
+	for limit := maxChainDepth(); err != nil && depth <= limit; depth++ {
+		switch typed := err.(type) {
+		case Error:
+			if typed.kind == kind && typed.module == module {
+				return true
+			}
+
+			err = typed.cause
+		case *Error:
+			if typed.kind == kind && typed.module == module {
+				return true
+			}
+
+			err = typed.cause
+		case interface{ Unwrap() error }:
+			err = typed.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, wrapped := range typed.Unwrap() {
+				if inChain(wrapped, kind, module, depth+1) {
+					return true
+				}
+			}
+
+			return false
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in the Error's chain that matches target, and if
+// so, sets target to that error value and returns true. Targets of type *Error
+// and **Error are filled with the Error itself, otherwise the chain of causes
+// is walked as the standard library does, so errors.As works through it.
+func (self Error) As(target any) bool {
+	switch target := target.(type) {
 	case *Error:
-		return err.Has(self)
-	default:
+		*target = self
+		return true
+	case **Error:
+		*target = &self
+		return true
+	}
+
+	if self.cause == nil {
 		return false
 	}
+
+	return goerrors.As(self.cause, target)
 }
 
 // String implements the Stringer interface.
 func (self Error) String() string {
 	causeMessage := ""
-	if self.cause != nil {
+	if self.cause != nil && !self.inlineCause {
 		causeMessage = ": " + self.cause.Error()
 	}
 
-	return self.message + causeMessage
+	if self.code != "" && _codePrefix.Load() {
+		return "[" + self.code + "] " + self.formatted() + causeMessage
+	}
+
+	return self.formatted() + causeMessage
+}
+
+// Summary returns a one-line chain of the messages of all errors wrapped within
+// the Error itself separated by arrows, capped to 256 characters, suitable for
+// alert titles and log summaries.
+func (self Error) Summary() string {
+	summary := self.formatted()
+
+	cause := self.cause
+	for depth := 1; cause != nil && depth < maxChainDepth(); depth++ {
+		switch err := cause.(type) {
+		case Error:
+			summary += " → " + err.formatted()
+			cause = err.cause
+		case *Error:
+			summary += " → " + err.formatted()
+			cause = err.cause
+		default:
+			summary += " → " + err.Error()
+			cause = nil
+		}
+	}
+
+	if runes := []rune(summary); len(runes) > _MAX_SUMMARY_LENGTH {
+		summary = string(runes[:_MAX_SUMMARY_LENGTH-1]) + "…"
+	}
+
+	return summary
 }
 
 // Error implements the Error interface.
@@ -217,154 +1166,111 @@ func (self Error) MarshalText() ([]byte, error) {
 	return []byte(self.String()), nil
 }
 
-// MarshalJSON implements the JSONMarshaler interface.
+// MarshalJSON implements the JSONMarshaler interface, encoding the Error as its
+// message string, or as structured JSON if set with SetJSONMode.
 func (self Error) MarshalJSON() ([]byte, error) {
-	return []byte("\"" + self.String() + "\""), nil
+	if JSONMode(_jsonMode.Load()) == JSONStructured {
+		return json.Marshal(newJSONError(self, JSONOptions{Verbose: true}, 1))
+	}
+
+	return json.Marshal(self.String())
 }
 
 // Format implements the Formatter interface:
 // - %s: Error message
+// - %q: Quoted error message
 // - %v: First error report
 // - %+v: All errors reports
-// - default: Error message
+// - %#v: Go-syntax representation of the type, package, extra... of all errors
+// - default: Bad verb notation with the error message, as the fmt package does
+// The widths, precisions and flags of %s and %q are respected.
 func (self Error) Format(format fmt.State, verb rune) {
 	switch verb {
-	case 's':
-		format.Write([]byte(self.String()))
+	case 's', 'q':
+		fmt.Fprintf(format, fmt.FormatString(format, verb), self.String())
 	case 'v':
-		if format.Flag('+') {
+		if format.Flag('#') {
+			format.Write([]byte(self.goString()))
+		} else if format.Flag('+') {
 			format.Write([]byte(self.StringReport()))
 		} else {
 			format.Write([]byte(self.StringReport(false)))
 		}
 	default:
-		format.Write([]byte(self.String()))
+		fmt.Fprintf(format, "%%!%c(errors.Error=%s)", verb, self.String())
 	}
 }
 
-func (self Error) stringReport(all bool, seenTraces map[string]bool) string {
-	report := ""
+// goString returns the Go-syntax representation of the Error and all errors
+// wrapped within itself for debugging.
+func (self Error) goString() string {
+	cause := "nil"
+	if self.cause != nil {
+		cause = fmt.Sprintf("%#v", self.cause)
+	}
 
-	if len(self.stackTrace) > 0 {
-		ellipsis := false
+	return fmt.Sprintf("errors.Error{Kind:%q, Module:%q, Code:%q, Level:%q, Message:%q, Extra:%#v, Tags:%#v, Cause:%s}",
+		self.kind, self.module, self.code, self.level.String(), self.formatted(), self.extra, self.tags, cause)
+}
 
+// SlogValue returns a structured log group containing all the information about
+// the first error and all errors wrapped within itself (including the types,
+// packages, messages, stack traces, extra, tags...).
+func (self Error) SlogValue() slog.Value {
+	return self.slogValue(1)
+}
+
+func (self Error) slogValue(depth int) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("kind", self.kind),
+		slog.String("module", self.module),
+		slog.String("message", self.formatted()),
+	}
+
+	if !self.raisedAt.IsZero() {
+		attrs = append(attrs, slog.String("raised_at", self.raisedAt.Format(time.RFC3339Nano)))
+	}
+
+	if len(self.stackTrace) > 0 {
+		stackTrace := make([]string, 0, len(self.stackTrace))
 		for i := len(self.stackTrace) - 1; i >= 0; i-- {
-			fileline := self.stackTrace[i].file + ":" + strconv.Itoa(self.stackTrace[i].line)
-
-			_, seen := seenTraces[fileline]
-			if !seen {
-				seenTraces[fileline] = true
-				report += "    " + fileline + "\n"
-				report += "        " + self.stackTrace[i].function + "\n"
-			} else if !ellipsis {
-				ellipsis = true
-				report += "    [...]\n"
-			}
+			stackTrace = append(stackTrace, self.stackTrace[i].File+":"+
+				strconv.Itoa(self.stackTrace[i].Line)+" "+self.stackTrace[i].Function)
 		}
-	} else {
-		report += "    (Stack trace not available)\n"
-	}
 
-	report += "\x1b[0;31m" + self.message + "\x1b[0m\n"
+		attrs = append(attrs, slog.Any("stack", stackTrace))
+	}
 
 	if len(self.extra) > 0 {
-		report += "    "
+		extra := make([]any, 0, len(self.extra))
 		for key, value := range self.extra {
-			report += key + "=" + fmt.Sprintf("%v", value) + " "
+			extra = append(extra, slog.Any(key, value))
 		}
-		report += "\n"
-	}
 
-	if all && self.cause != nil {
-		report += "\nCaused by the following error:\n"
-		switch cause := self.cause.(type) {
-		case Error:
-			report += cause.stringReport(all, seenTraces)
-		case *Error:
-			report += cause.stringReport(all, seenTraces)
-		default:
-			report += "    (Stack trace not available)\n"
-			report += "\x1b[0;31m" + cause.Error() + "\x1b[0m (" +
-				strings.TrimPrefix(reflect.TypeOf(cause).String(), "*") + ")\n"
-		}
+		attrs = append(attrs, slog.Group("extra", extra...))
 	}
 
-	return report
-}
+	if len(self.tags) > 0 {
+		tags := make([]any, 0, len(self.tags))
+		for key, value := range self.tags {
+			tags = append(tags, slog.String(key, value))
+		}
 
-// StringReport returns a string containing all the information about the first
-// error (including the message, stack trace, extra...) or about all errors
-// wrapped within the Error itself (default is all).
-func (self Error) StringReport(all ...bool) string {
-	_all := true
-	if len(all) > 0 {
-		_all = all[0]
+		attrs = append(attrs, slog.Group("tags", tags...))
 	}
 
-	seenTraces := make(map[string]bool)
-
-	report := "\x1b[1;91m" + self.String() + "\x1b[0m\n\n"
-	report += "Traceback (most recent call last):\n"
-	report += self.stringReport(_all, seenTraces)
-
-	return report
-}
-
-func (self Error) sentryReport(report *sentry.Event) {
-	if self.cause != nil {
+	if self.cause != nil && depth >= maxChainDepth() {
+		attrs = append(attrs, slog.String("cause", truncatedChain(depth)))
+	} else if self.cause != nil {
 		switch cause := self.cause.(type) {
 		case Error:
-			cause.sentryReport(report)
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: cause.slogValue(depth + 1)})
 		case *Error:
-			cause.sentryReport(report)
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: cause.slogValue(depth + 1)})
 		default:
-			report.Exception = append(report.Exception, sentry.Exception{
-				Type:  strings.TrimPrefix(reflect.TypeOf(cause).String(), "*"),
-				Value: cause.Error(),
-			})
-		}
-	}
-
-	for key, value := range self.extra {
-		report.Extra[key] = value
-	}
-
-	for key, value := range self.tags {
-		report.Tags[key] = value
-	}
-
-	var stackTrace *sentry.Stacktrace
-	if len(self.stackTrace) > 0 {
-		stackTrace = &sentry.Stacktrace{
-			Frames: make([]sentry.Frame, 0, len(self.stackTrace)),
-		}
-
-		for i := len(self.stackTrace) - 1; i >= 0; i-- {
-			stackTrace.Frames = append(stackTrace.Frames, sentry.NewFrame(runtime.Frame{
-				Function: self.stackTrace[i].function,
-				File:     self.stackTrace[i].file,
-				Line:     self.stackTrace[i].line,
-			}))
+			attrs = append(attrs, slog.String("cause", cause.Error()))
 		}
 	}
 
-	report.Exception = append(report.Exception, sentry.Exception{
-		Type:       self.kind,
-		Value:      self.String(),
-		Module:     self.module,
-		Stacktrace: stackTrace,
-	})
-}
-
-// SentryReport returns a Sentry Event containing all the information about the
-// first error and all errors wrapped within itself (including the types, packages
-// messages, stack traces, extra, tags...).
-func (self Error) SentryReport() *sentry.Event {
-	report := sentry.NewEvent()
-	report.Level = sentry.LevelError
-	report.Message = _ANSI_COLOR_PATTERN.ReplaceAllString(self.StringReport(), "")
-	report.Tags["package"] = self.module
-	self.sentryReport(report)
-
-	return report
+	return slog.GroupValue(attrs...)
 }
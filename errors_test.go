@@ -1,17 +1,22 @@
+//go:build !tinygo && !errors_headless
+
 package errors_test
 
 import (
+	"bytes"
 	goerrors "errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/neoxelox/errors"
 )
 
-var ErrOtherLibrary = goerrors.New("other library error")
-var ErrUserNotFound = errors.New("user %s not found")
-var ErrCannotDeposit = errors.New("cannot deposit")
-
 func TestPrint(t *testing.T) {
 	t.Parallel()
 
@@ -41,11 +46,11 @@ func TestPrint(t *testing.T) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrUserNotFound) {
+	if !ErrUserNotFound.In(cerr) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrCannotDeposit) {
+	if !ErrCannotDeposit.In(cerr) {
 		t.FailNow()
 	}
 
@@ -82,11 +87,11 @@ func TestString(t *testing.T) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrUserNotFound) {
+	if !ErrUserNotFound.In(cerr) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrCannotDeposit) {
+	if !ErrCannotDeposit.In(cerr) {
 		t.FailNow()
 	}
 
@@ -123,11 +128,11 @@ func TestSentry(t *testing.T) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrUserNotFound) {
+	if !ErrUserNotFound.In(cerr) {
 		t.FailNow()
 	}
 
-	if !cerr.Has(ErrCannotDeposit) {
+	if !ErrCannotDeposit.In(cerr) {
 		t.FailNow()
 	}
 
@@ -135,31 +140,862 @@ func TestSentry(t *testing.T) {
 	fmt.Printf("%+v", cerr.SentryReport())
 }
 
-func view() error {
-	err := usecase()
-	if err != nil {
-		return ErrCannotDeposit.Raise().
-			With("cannot add money to account %s", "ARN3107").Tags(map[string]any{"apiVersion": 2}).Cause(err)
+func TestSentryConcise(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+	if err == nil {
+		t.FailNow()
+	}
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	report := cerr.SentryReport(true)
+
+	if strings.Contains(report.Message, "\n") || strings.Contains(report.Message, "\x1b") {
+		t.FailNow()
+	}
+
+	full, ok := report.Extra["report"].(string)
+	if !ok || strings.Contains(full, "\x1b") {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestSentryANSIFree(t *testing.T) {
+	errors.SetTheme(errors.LightTheme)
+	defer errors.SetTheme(errors.DarkTheme)
+
+	err, ok := view().(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	report := err.SentryReport()
+	if strings.Contains(report.Message, "\x1b") {
+		t.FailNow()
+	}
+
+	for _, exception := range report.Exception {
+		if strings.Contains(exception.Value, "\x1b") {
+			t.FailNow()
+		}
+	}
+}
+
+func TestCompactReport(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+	if err == nil {
+		t.FailNow()
+	}
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	report := cerr.CompactReport()
+
+	if strings.Contains(report, "\n") || !strings.Contains(report, "userID=310700") {
+		t.FailNow()
+	}
+}
+
+type otherLibraryError struct {
+	code int
+}
+
+func (self otherLibraryError) Error() string {
+	return "other library error " + fmt.Sprint(self.code)
+}
+
+func TestAs(t *testing.T) {
+	t.Parallel()
+
+	err := error(ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex").Cause(otherLibraryError{code: 42})))
+
+	var cerr errors.Error
+	if !goerrors.As(err, &cerr) || !ErrCannotDeposit.Is(cerr) {
+		t.FailNow()
+	}
+
+	var oerr otherLibraryError
+	if !goerrors.As(err, &oerr) || oerr.code != 42 {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestStackCapture(t *testing.T) {
+	errors.SetStackCapture(false)
+	defer errors.SetStackCapture(true)
+
+	err := ErrUserNotFound.Raise("Alex").Skip(1)
+
+	if !strings.Contains(err.StringReport(), "(Stack trace not available)") {
+		t.FailNow()
+	}
+
+	if !ErrUserNotFound.Is(err) {
+		t.FailNow()
+	}
+}
+
+type libraryFrame uintptr
+
+type libraryStackTrace []libraryFrame
+
+type libraryStackError struct {
+	stack []uintptr
+}
+
+func (self libraryStackError) Error() string {
+	return "library stack error"
+}
+
+func (self libraryStackError) StackTrace() libraryStackTrace {
+	stackTrace := make(libraryStackTrace, 0, len(self.stack))
+	for _, pc := range self.stack {
+		stackTrace = append(stackTrace, libraryFrame(pc))
+	}
+
+	return stackTrace
+}
+
+func stackedLibrary() error {
+	stack := make([]uintptr, 32)
+	length := runtime.Callers(1, stack)
+
+	return libraryStackError{stack: stack[:length]}
+}
+
+func TestForeignStackTrace(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Cause(stackedLibrary())
+
+	if !strings.Contains(err.StringReport(), "stackedLibrary") {
+		t.FailNow()
+	}
+
+	exceptions := err.SentryReport().Exception
+	if len(exceptions) != 2 || exceptions[0].Stacktrace == nil {
+		t.FailNow()
+	}
+}
+
+func TestAttach(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex").
+		Attach("query.sql", "application/sql", []byte("SELECT * FROM users;")))
+
+	attachments := err.SentryReport().Attachments
+	if len(attachments) != 1 || attachments[0].Filename != "query.sql" {
+		t.FailNow()
+	}
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+	if err == nil {
+		t.FailNow()
+	}
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	if cerr.Summary() != "cannot deposit: cannot add money to account ARN3107 → user Alex not found → other library error" {
+		t.FailNow()
+	}
+}
+
+func TestGetExtraAs(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+
+	userID, ok := errors.GetExtraAs[int](err, "userID")
+	if !ok || userID != 310700 {
+		t.FailNow()
+	}
+
+	if _, ok := errors.GetExtraAs[string](err, "userID"); ok {
+		t.FailNow()
+	}
+
+	if _, ok := errors.GetExtraAs[string](err, "missing"); ok {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestFrameAggregation(t *testing.T) {
+	errors.SetFrameAggregation(true)
+	defer errors.SetFrameAggregation(false)
+
+	var err error = ErrOtherLibrary
+	for i := 0; i < 3; i++ {
+		err = ErrCannotDeposit.Raise().Cause(err)
+	}
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	if !strings.Contains(cerr.StringReport(), "identical frames omitted]") {
+		t.FailNow()
+	}
+
+	exceptions := cerr.SentryReport().Exception
+	frames := exceptions[len(exceptions)-1].Stacktrace.Frames
+	if !strings.Contains(frames[len(frames)-1].Function, "identical frames omitted]") {
+		t.FailNow()
+	}
+}
+
+func TestRaiseLazy(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.RaiseLazy("Alex")
+
+	if !ErrUserNotFound.Is(err) || err.Error() != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if err.With("retrying").Error() != "user Alex not found: retrying" {
+		t.FailNow()
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	t.Parallel()
+
+	if !errors.SameOrigin(repository(), repository()) {
+		t.FailNow()
+	}
+
+	if errors.SameOrigin(repository(), view()) {
+		t.FailNow()
+	}
+
+	if errors.SameOrigin(ErrOtherLibrary, ErrOtherLibrary) {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestFrameFormatter(t *testing.T) {
+	errors.SetFrameFormatter(func(frame errors.Frame) string {
+		return "vscode://file" + frame.File + ":" + fmt.Sprint(frame.Line)
+	})
+	defer errors.SetFrameFormatter(nil)
+
+	if !strings.Contains(ErrUserNotFound.Raise("Alex").StringReport(), "    vscode://file/") {
+		t.FailNow()
 	}
+}
 
-	return nil
+type userRepository struct{}
+
+func (self *userRepository) find() errors.Template {
+	return errors.New("not found")
 }
 
-func usecase() error {
-	err := repository()
-	if err != nil {
-		return err
+func TestModule(t *testing.T) {
+	t.Parallel()
+
+	if ErrUserNotFound.Module() != "github.com/neoxelox/errors_test" {
+		t.FailNow()
+	}
+
+	if (&userRepository{}).find().Module() != "github.com/neoxelox/errors_test" {
+		t.FailNow()
 	}
 
-	return nil
+	template := errors.New("billing failed").WithModule("billing")
+	if template.Module() != "billing" || !template.Is(template.Raise()) {
+		t.FailNow()
+	}
 }
 
-func repository() error {
-	err := ErrOtherLibrary
-	if err != nil {
-		return ErrUserNotFound.Raise("Alex").
-			Extra(map[string]any{"userID": 310700, "accountID": "ARN3107"}).Cause(err)
+func TestReportOptions(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+	if err == nil {
+		t.FailNow()
+	}
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	report := cerr.Report(errors.ReportOptions{All: true, MaxFrames: 1, SourceContext: 1})
+
+	if strings.Contains(report, "\x1b") || !strings.Contains(report, "frames omitted]") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(report, "> ") || !strings.Contains(report, "ErrCannotDeposit.Raise()") {
+		t.FailNow()
+	}
+}
+
+func TestReportTo(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("a very long user name that overflows narrow terminals")
+
+	var buffer bytes.Buffer
+	if _, werr := err.ReportTo(&buffer); werr != nil {
+		t.FailNow()
+	}
+
+	if strings.Contains(buffer.String(), "\x1b") || buffer.String() != err.Report(errors.ReportOptions{All: true}) {
+		t.FailNow()
+	}
+
+	file, ferr := os.CreateTemp(t.TempDir(), "report")
+	if ferr != nil {
+		t.FailNow()
+	}
+	defer file.Close()
+
+	if _, werr := err.ReportTo(file); werr != nil {
+		t.FailNow()
+	}
+
+	if content, rerr := os.ReadFile(file.Name()); rerr != nil || strings.Contains(string(content), "\x1b") {
+		t.FailNow()
+	}
+
+	for _, line := range strings.Split(err.Report(errors.ReportOptions{All: true, Color: true, Width: 40}), "\n") {
+		if len(regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(line, "")) > 40 {
+			t.FailNow()
+		}
+	}
+	wrapped := err.Report(errors.ReportOptions{Width: 40})
+	if !strings.Contains(wrapped, "\nuser a very long user name that\noverflows narrow terminals not found\n") {
+		t.FailNow()
+	}
+
+	extra := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"requestPath": "/api/v1/accounts/ARN3107/deposits"})
+	if !strings.Contains(extra.Report(errors.ReportOptions{Width: 40}), "\n    requestPath=/api/v1/accounts/\n    ARN3107/deposits \n") {
+		t.FailNow()
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Parallel()
+
+	template := errors.NewWithOptions("payment declined", errors.NewOptions{
+		Code:   "PAYMENT_DECLINED",
+		Module: "billing",
+	})
+
+	err := template.Raise()
+	if err.Code() != "PAYMENT_DECLINED" || err.Module() != "billing" || len(err.StackTrace()) == 0 {
+		t.FailNow()
+	}
+
+	if len(errors.NewWithOptions("payment declined", errors.NewOptions{DisableStack: true}).Raise().StackTrace()) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	t.Parallel()
+
+	queue := make(chan *errors.Error, 1)
+	go func() {
+		queue <- ErrCannotDeposit.Raise()
+	}()
+
+	err := (<-queue).Rewrap()
+
+	report := err.Report(errors.ReportOptions{})
+	if !strings.Contains(report, "Observed here") || !strings.Contains(report, "TestRewrap") {
+		t.FailNow()
+	}
+
+	if len(err.SentryReport().Exception) != 2 {
+		t.FailNow()
+	}
+}
+
+func TestStdlibIs(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+
+	if !goerrors.Is(err, ErrCannotDeposit.Target()) || !goerrors.Is(err, ErrUserNotFound.Target()) {
+		t.FailNow()
 	}
 
-	return nil
+	if !goerrors.Is(err, ErrOtherLibrary) {
+		t.FailNow()
+	}
+
+	if goerrors.Is(ErrUserNotFound.Raise("Alex"), ErrCannotDeposit.Target()) {
+		t.FailNow()
+	}
+
+	if !ErrUserNotFound.Is(ErrUserNotFound.Raise("Alex")) {
+		t.FailNow()
+	}
+}
+
+func TestBreadcrumb(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex")
+	for i := 0; i < 40; i++ {
+		err.Breadcrumb(fmt.Sprintf("step %d", i), map[string]any{"attempt": i})
+	}
+
+	report := err.Report(errors.ReportOptions{})
+	if !strings.Contains(report, "Breadcrumbs:") || strings.Contains(report, "step 7 ") {
+		t.FailNow()
+	}
+
+	if strings.Index(report, "step 8 attempt=8") > strings.Index(report, "step 39 attempt=39") {
+		t.FailNow()
+	}
+
+	breadcrumbs := err.SentryReport().Breadcrumbs
+	if len(breadcrumbs) != 32 || breadcrumbs[31].Message != "step 39" {
+		t.FailNow()
+	}
+}
+
+func raiseHelper() *errors.Error {
+	return ErrCannotDeposit.Raise()
+}
+
+func TestSkipFunc(t *testing.T) {
+	t.Parallel()
+
+	err := raiseHelper().SkipFunc(`\.raiseHelper$`)
+	if !strings.HasSuffix(err.StackTrace()[0].Function, ".TestSkipFunc") {
+		t.FailNow()
+	}
+
+	err = raiseHelper().SkipWhile(func(errors.Frame) bool { return true })
+	if len(err.StackTrace()) != 0 {
+		t.FailNow()
+	}
+
+	err = raiseHelper().SkipFunc("(")
+	if !strings.HasSuffix(err.StackTrace()[0].Function, ".raiseHelper") {
+		t.FailNow()
+	}
+}
+
+func TestChainCycle(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise()
+	err.Cause(ErrUserNotFound.Raise("Alex").Cause(err))
+
+	if !errors.ErrChainCycle.In(err) || !strings.Contains(err.StringReport(), "error chain cycle detected") {
+		t.FailNow()
+	}
+
+	err = ErrCannotDeposit.Raise()
+	err.Cause(err)
+
+	if !errors.ErrChainCycle.In(err) {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestMaxChainDepth(t *testing.T) {
+	errors.SetMaxChainDepth(2)
+	defer errors.SetMaxChainDepth(64)
+
+	err := ErrCannotDeposit.Raise().Cause(ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex")))
+
+	if ErrUserNotFound.In(err) || !strings.Contains(err.StringReport(), "[... chain truncated at 2 errors]") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Extra["chain"] != "[... chain truncated at 2 errors]" {
+		t.FailNow()
+	}
+
+	if err.Summary() != "cannot deposit → cannot deposit" ||
+		err.Hash() != ErrCannotDeposit.Raise().Cause(ErrCannotDeposit.Raise()).Hash() {
+		t.FailNow()
+	}
+
+	var log bytes.Buffer
+	slog.New(slog.NewTextHandler(&log, nil)).Error("failed", slog.Any("error", err.SlogValue()))
+
+	if strings.Contains(log.String(), "user Alex not found") ||
+		!strings.Contains(log.String(), `error.cause.cause="[... chain truncated at 2 errors]"`) {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestTheme(t *testing.T) {
+	errors.SetTheme(errors.MonochromeTheme)
+	defer errors.SetTheme(errors.DarkTheme)
+
+	report := ErrCannotDeposit.Raise().StringReport()
+	if strings.Contains(report, "\x1b[1;91m") || !strings.HasPrefix(report, "\x1b[1mcannot deposit\x1b[0m") {
+		t.FailNow()
+	}
+
+	errors.SetTheme(errors.LightTheme)
+
+	report = ErrCannotDeposit.Raise().StringReport()
+	if !strings.Contains(report, "\x1b[0;34m") {
+		t.FailNow()
+	}
+}
+
+func TestOp(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Op("view").Cause(
+		ErrCannotDeposit.Raise().Cause(
+			ErrUserNotFound.Raise("Alex").Op("repository").Cause(ErrOtherLibrary)))
+
+	if err.OpPath() != "view → repository" {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.Report(errors.ReportOptions{}), "Operation: view → repository\n") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Tags["operation"] != "view → repository" {
+		t.FailNow()
+	}
+
+	if ErrCannotDeposit.Raise().OpPath() != "" {
+		t.FailNow()
+	}
+}
+
+func TestDeclaredAt(t *testing.T) {
+	t.Parallel()
+
+	if !strings.Contains(ErrUserNotFound.DeclaredAt(), "fixtures_test.go:") {
+		t.FailNow()
+	}
+
+	if ErrUserNotFound.Raise("Alex").DeclaredAt() != ErrUserNotFound.DeclaredAt() {
+		t.FailNow()
+	}
+}
+
+func BenchmarkRaise(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = ErrCannotDeposit.Raise()
+	}
+}
+
+var ErrCacheMiss = errors.New("cache miss", false)
+
+func BenchmarkRaiseWithoutStack(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = ErrCacheMiss.Raise()
+	}
+}
+
+func TestHasForeign(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Cause(fmt.Errorf("wrapped: %w", ErrOtherLibrary))
+	if !err.Has(ErrOtherLibrary) || err.Has(goerrors.New(ErrOtherLibrary.Error())) {
+		t.FailNow()
+	}
+
+	err = ErrCannotDeposit.Raise().Cause(fmt.Errorf("wrapped: %w", ErrUserNotFound.Raise("Alex")))
+	if !ErrUserNotFound.In(err) || errors.NotFound.In(err) {
+		t.FailNow()
+	}
+}
+
+func deepChain(depth int, cause error) *errors.Error {
+	err := ErrUserNotFound.Raise("Alex").Cause(cause)
+	for i := 1; i < depth; i++ {
+		err = ErrCannotDeposit.Raise().Cause(err)
+	}
+
+	return err
+}
+
+func BenchmarkHas(b *testing.B) {
+	errors.SetStackCapture(false)
+	defer errors.SetStackCapture(true)
+
+	err := deepChain(50, ErrOtherLibrary)
+
+	b.Run("Kind", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = ErrUserNotFound.In(err)
+		}
+	})
+
+	b.Run("Foreign", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = err.Has(ErrOtherLibrary)
+		}
+	})
+
+	b.Run("Missing", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = errors.NotFound.In(err)
+		}
+	})
+}
+
+// nolint:paralleltest
+func TestInAllocs(t *testing.T) {
+	err := deepChain(20, fmt.Errorf("wrapped: %w", ErrCacheMiss.Raise()))
+
+	if !ErrCacheMiss.In(err) || errors.NotFound.In(err) {
+		t.FailNow()
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = ErrCacheMiss.In(err)
+		_ = errors.NotFound.In(err)
+	})
+	if allocs != 0 {
+		t.FailNow()
+	}
+}
+
+func TestRaisedAt(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+	cause := ErrUserNotFound.Raise("Alex")
+	err := ErrCannotDeposit.Raise().Cause(cause)
+
+	if cause.RaisedAt().Before(before) || err.RaisedAt().Before(cause.RaisedAt()) {
+		t.FailNow()
+	}
+
+	if err.SinceCause() != err.RaisedAt().Sub(cause.RaisedAt()) || cause.SinceCause() != 0 {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.StringReport(), "    Raised at: "+err.RaisedAt().Format(time.RFC3339Nano)+"\n") {
+		t.FailNow()
+	}
+}
+
+func TestAllExtras(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"amount": 10}).Tags(map[string]any{"region": "eu"}).
+		Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"amount": 20, "userID": 310700}).
+			Tags(map[string]any{"region": "us", "tier": "gold"}))
+
+	extras := err.AllExtras()
+	if len(extras) != 2 || extras["amount"] != 10 || extras["userID"] != 310700 {
+		t.FailNow()
+	}
+
+	tags := err.AllTags()
+	if len(tags) != 2 || tags["region"] != "eu" || tags["tier"] != "gold" {
+		t.FailNow()
+	}
+
+	extras = err.AllExtras(true)
+	if len(extras) != 3 || extras["0.amount"] != 10 || extras["1.amount"] != 20 {
+		t.FailNow()
+	}
+
+	if tags = err.AllTags(true); len(tags) != 3 || tags["1.region"] != "us" {
+		t.FailNow()
+	}
+}
+
+func TestReportRuntimeFrames(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise()
+
+	report := err.Report(errors.ReportOptions{})
+	if strings.Contains(report, "runtime.goexit") || !strings.Contains(report, "testing.tRunner") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.Report(errors.ReportOptions{RuntimeFrames: true}), "runtime.goexit") {
+		t.FailNow()
+	}
+
+	stackTrace := err.StackTrace()
+	if stackTrace[len(stackTrace)-1].Function != "runtime.goexit" {
+		t.FailNow()
+	}
+}
+
+func TestStringReportN(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"amount": 10}).Cause(view())
+
+	full := err.Report(errors.ReportOptions{All: true})
+	if err.StringReportN(len(full)) != full {
+		t.FailNow()
+	}
+
+	for _, maxBytes := range []int{600, 300, 120, 30} {
+		report := err.StringReportN(maxBytes)
+
+		if len(report) > maxBytes || !strings.Contains(report, "[... report truncated]\n") ||
+			!strings.HasPrefix(report, "cannot") {
+			t.FailNow()
+		}
+	}
+
+	// Each error keeps its message and the innermost one is kept whole
+	report := err.StringReportN(600)
+	if !strings.Contains(report, "amount=10") || !strings.Contains(report, "\nuser Alex not found\n") ||
+		!strings.HasSuffix(report, "other library error (errors.errorString)\n") {
+		t.FailNow()
+	}
+
+	if report := err.StringReportN(300); !strings.HasSuffix(report, "other library error (errors.errorString)\n") {
+		t.FailNow()
+	}
+
+	if err.StringReportN(10) != "[... repor" {
+		t.FailNow()
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 42})
+
+	if fmt.Sprintf("%s|%q|%25s|%-20s|", err, err, err, err) !=
+		`user Alex not found|"user Alex not found"|      user Alex not found|user Alex not found |` {
+		t.FailNow()
+	}
+
+	if fmt.Sprintf("%d", err) != "%!d(errors.Error=user Alex not found)" {
+		t.FailNow()
+	}
+
+	dump := fmt.Sprintf("%#v", ErrCannotDeposit.Raise().Cause(err))
+	if !strings.HasPrefix(dump, `errors.Error{Kind:"cannot deposit", Module:"github.com/neoxelox/errors_test"`) ||
+		!strings.Contains(dump, `Cause:errors.Error{Kind:"user %s not found"`) ||
+		!strings.Contains(dump, `Extra:map[string]interface {}{"userID":42}`) {
+		t.FailNow()
+	}
+
+	if fmt.Sprintf("%v", err) != err.StringReport(false) || fmt.Sprintf("%+v", err) != err.StringReport() {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestDoubleRaise(t *testing.T) {
+	var log bytes.Buffer
+
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&log, nil)))
+	errors.SetDoubleRaiseWarning(true)
+
+	defer func() {
+		slog.SetDefault(logger)
+		errors.SetDoubleRaiseWarning(false)
+		errors.SetStackCapture(true)
+	}()
+
+	err := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 42})
+	reraised := err.Raise("Bob")
+
+	if reraised == err || reraised.Error() != "user Alex not found" || reraised.Extras()["userID"] != 42 {
+		t.FailNow()
+	}
+
+	if !reraised.RaisedAt().Equal(err.RaisedAt()) || len(reraised.StackTrace()) != len(err.StackTrace()) {
+		t.FailNow()
+	}
+
+	if !strings.Contains(reraised.StringReport(), "Observed here") || strings.Contains(err.StringReport(), "Observed here") {
+		t.FailNow()
+	}
+
+	reraised.Extra(map[string]any{"userID": 7})
+	if err.Extras()["userID"] != 42 {
+		t.FailNow()
+	}
+
+	if !strings.Contains(log.String(), "already raised") || !strings.Contains(log.String(), "errors_test.go") {
+		t.FailNow()
+	}
+
+	if ErrUserNotFound.Raise("Bob").Error() != "user Bob not found" {
+		t.FailNow()
+	}
+
+	// The new call site is added to where it was already observed
+	again := reraised.Raise()
+	if strings.Count(again.StringReport(), "TestDoubleRaise") !=
+		strings.Count(reraised.StringReport(), "TestDoubleRaise")+1 {
+		t.FailNow()
+	}
+
+	errors.SetStackCapture(false)
+	log.Reset()
+
+	if strings.Contains(ErrUserNotFound.Raise("Alex").Raise().StringReport(), "Observed here") ||
+		!strings.Contains(log.String(), "errors_test.go") {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestSetCodePrefix(t *testing.T) {
+	defer errors.SetCodePrefix(false)
+
+	template := errors.NewWithOptions("user %s not found", errors.NewOptions{Code: "USER_NOT_FOUND"})
+	err := ErrCannotDeposit.Raise().Cause(template.Raise("Alex"))
+
+	if err.Error() != "cannot deposit: user Alex not found" {
+		t.FailNow()
+	}
+
+	errors.SetCodePrefix(true)
+
+	if template.Raise("Alex").String() != "[USER_NOT_FOUND] user Alex not found" ||
+		err.Error() != "cannot deposit: [USER_NOT_FOUND] user Alex not found" ||
+		fmt.Sprintf("%s", err) != err.Error() {
+		t.FailNow()
+	}
 }
@@ -0,0 +1,257 @@
+// Package errorsbreaker implements a circuit breaker that trips on the types and
+// levels of the errors returned by the protected calls.
+package errorsbreaker
+
+import (
+	goerrors "errors"
+	"sync"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_DEFAULT_THRESHOLD = 5
+	_DEFAULT_WINDOW    = time.Minute
+	_DEFAULT_COOLDOWN  = 30 * time.Second
+)
+
+var (
+	// ErrCircuitOpen is raised when a call is rejected by an open circuit breaker,
+	// wrapping the errors that tripped it.
+	ErrCircuitOpen = errors.New("circuit breaker %s is open", false)
+	// ErrCallPanicked is recorded as the failure of a call through Do that panicked.
+	ErrCallPanicked = errors.New("call through circuit breaker %s panicked")
+)
+
+// State represents the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = iota
+	// StateOpen rejects every call until the cooldown elapses.
+	StateOpen
+	// StateHalfOpen lets a single trial call through, which closes the breaker if
+	// it succeeds or opens it again otherwise.
+	StateHalfOpen
+)
+
+// String implements the Stringer interface.
+func (self State) String() string {
+	switch self {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Options represents the options of a Breaker.
+type Options struct {
+	// Kinds are the types of the errors counted (default is any).
+	Kinds []errors.Template
+	// MinLevel is the minimum level of the errors counted, foreign errors having
+	// the error level (default is error).
+	MinLevel errors.Level
+	// Threshold is the number of counted errors within the window that trips the
+	// breaker (default is 5).
+	Threshold int
+	// Window is the sliding window of time the errors are counted in (default is
+	// one minute).
+	Window time.Duration
+	// Cooldown is the time the breaker stays open before letting a trial call
+	// through (default is 30 seconds).
+	Cooldown time.Duration
+}
+
+type failure struct {
+	at  time.Time
+	err error
+}
+
+// Breaker is a circuit breaker that opens when too many errors of the configured
+// types and levels are recorded within a window of time.
+type Breaker struct {
+	name     string
+	options  Options
+	mutex    sync.Mutex
+	state    State
+	trial    bool
+	openedAt time.Time
+	failures []failure
+}
+
+// New creates a new closed Breaker with a name, shown in its ErrCircuitOpen
+// errors, and optional options.
+func New(name string, options ...Options) *Breaker {
+	_options := Options{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.Threshold <= 0 {
+		_options.Threshold = _DEFAULT_THRESHOLD
+	}
+
+	if _options.Window <= 0 {
+		_options.Window = _DEFAULT_WINDOW
+	}
+
+	if _options.Cooldown <= 0 {
+		_options.Cooldown = _DEFAULT_COOLDOWN
+	}
+
+	return &Breaker{
+		name:    name,
+		options: _options,
+		state:   StateClosed,
+	}
+}
+
+// counts returns whether the error is counted by the breaker.
+func (self *Breaker) counts(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if len(self.options.Kinds) > 0 && !errors.HasAny(err, self.options.Kinds...) {
+		return false
+	}
+
+	level := errors.LevelError
+
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		level = rerr.Level()
+	}
+
+	return level >= self.options.MinLevel
+}
+
+// State returns the current state of the breaker.
+func (self *Breaker) State() State {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.state == StateOpen && time.Since(self.openedAt) >= self.options.Cooldown {
+		return StateHalfOpen
+	}
+
+	return self.state
+}
+
+// Allow returns an ErrCircuitOpen error if the breaker rejects a call, or nil if
+// the call can go through, in which case its result must be given to Record.
+func (self *Breaker) Allow() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.state == StateOpen && time.Since(self.openedAt) >= self.options.Cooldown {
+		self.state = StateHalfOpen
+		self.trial = false
+	}
+
+	switch self.state {
+	case StateClosed:
+		return nil
+	case StateHalfOpen:
+		if !self.trial {
+			self.trial = true
+			return nil
+		}
+	}
+
+	causes := make([]error, 0, len(self.failures))
+	for _, failure := range self.failures {
+		causes = append(causes, failure.err)
+	}
+
+	return ErrCircuitOpen.Raise(self.name).Extra(map[string]any{
+		"breaker":  self.name,
+		"failures": len(causes),
+		"retry_in": max(self.options.Cooldown-time.Since(self.openedAt), 0).String(),
+	}).Cause(goerrors.Join(causes...))
+}
+
+// Record records the result of a call let through by Allow, tripping the breaker
+// when the counted errors reach the threshold within the window, or closing it
+// when a trial call doesn't fail with a counted error.
+func (self *Breaker) Record(err error) {
+	self.record(err, self.counts(err))
+}
+
+func (self *Breaker) record(err error, counted bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	now := time.Now()
+
+	switch self.state {
+	case StateHalfOpen:
+		self.trial = false
+
+		if !counted {
+			self.state = StateClosed
+			self.failures = nil
+
+			return
+		}
+
+		self.failures = append(self.failures[max(len(self.failures)-self.options.Threshold+1, 0):],
+			failure{at: now, err: err})
+		self.state = StateOpen
+		self.openedAt = now
+	case StateClosed:
+		if !counted {
+			return
+		}
+
+		failures := self.failures[:0]
+		for _, failure := range self.failures {
+			if now.Sub(failure.at) < self.options.Window {
+				failures = append(failures, failure)
+			}
+		}
+
+		self.failures = append(failures, failure{at: now, err: err})
+
+		if len(self.failures) >= self.options.Threshold {
+			self.state = StateOpen
+			self.openedAt = now
+		}
+	case StateOpen:
+	}
+}
+
+// Do calls the function if the breaker allows it, recording its result, or
+// returns an ErrCircuitOpen error otherwise. A panic of the function is recorded
+// as a counted failure before panicking again.
+func (self *Breaker) Do(call func() error) (err error) {
+	if err := self.Allow(); err != nil {
+		return err
+	}
+
+	panicked := true
+
+	defer func() {
+		if !panicked {
+			self.Record(err)
+			return
+		}
+
+		recovered := recover()
+		self.record(ErrCallPanicked.Raise(self.name).With("%v", recovered), true)
+
+		panic(recovered)
+	}()
+
+	err = call()
+	panicked = false
+
+	return err
+}
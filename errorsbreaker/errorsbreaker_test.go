@@ -0,0 +1,138 @@
+package errorsbreaker_test
+
+import (
+	goerrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsbreaker"
+)
+
+var (
+	ErrTimeout  = errors.New("upstream timed out")
+	ErrNotFound = errors.New("resource not found")
+)
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+
+	breaker := errorsbreaker.New("upstream", errorsbreaker.Options{
+		Kinds:     []errors.Template{ErrTimeout},
+		Threshold: 2,
+		Cooldown:  20 * time.Millisecond,
+	})
+
+	for i := 0; i < 5; i++ {
+		if breaker.Do(func() error { return ErrNotFound.Raise() }) == nil {
+			t.FailNow()
+		}
+	}
+
+	if breaker.State() != errorsbreaker.StateClosed {
+		t.FailNow()
+	}
+
+	for i := 0; i < 2; i++ {
+		if !ErrTimeout.Is(breaker.Do(func() error { return ErrTimeout.Raise() })) {
+			t.FailNow()
+		}
+	}
+
+	if breaker.State() != errorsbreaker.StateOpen {
+		t.FailNow()
+	}
+
+	called := false
+
+	err := breaker.Do(func() error {
+		called = true
+		return nil
+	})
+	if called || !errorsbreaker.ErrCircuitOpen.Is(err) || !goerrors.Is(err, ErrTimeout.Target()) {
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(err.Error(), "circuit breaker upstream is open: upstream timed out") {
+		t.FailNow()
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if breaker.State() != errorsbreaker.StateHalfOpen {
+		t.FailNow()
+	}
+
+	if breaker.Do(func() error { return ErrTimeout.Raise() }) == nil || breaker.State() != errorsbreaker.StateOpen {
+		t.FailNow()
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if breaker.Allow() != nil || !errorsbreaker.ErrCircuitOpen.Is(breaker.Allow()) {
+		t.FailNow()
+	}
+
+	breaker.Record(nil)
+
+	if breaker.State() != errorsbreaker.StateClosed || breaker.Do(func() error { return nil }) != nil {
+		t.FailNow()
+	}
+}
+
+func TestBreakerLevels(t *testing.T) {
+	t.Parallel()
+
+	warning := errors.NewWarning("degraded")
+	breaker := errorsbreaker.New("levels", errorsbreaker.Options{Threshold: 1, MinLevel: errors.LevelError})
+
+	breaker.Record(warning.Raise())
+	if breaker.State() != errorsbreaker.StateClosed {
+		t.FailNow()
+	}
+
+	breaker.Record(goerrors.New("foreign"))
+	if breaker.State() != errorsbreaker.StateOpen {
+		t.FailNow()
+	}
+}
+
+func TestBreakerPanic(t *testing.T) {
+	t.Parallel()
+
+	breaker := errorsbreaker.New("panics", errorsbreaker.Options{
+		Kinds:     []errors.Template{ErrTimeout},
+		Threshold: 1,
+		Cooldown:  20 * time.Millisecond,
+	})
+
+	panics := func() (recovered any) {
+		defer func() { recovered = recover() }()
+
+		_ = breaker.Do(func() error { panic("boom") })
+
+		return nil
+	}
+
+	if panics() != "boom" || breaker.State() != errorsbreaker.StateOpen {
+		t.FailNow()
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// The panicking trial call opens the breaker again instead of blocking the next trials
+	if panics() != "boom" || breaker.State() != errorsbreaker.StateOpen {
+		t.FailNow()
+	}
+
+	if err := breaker.Allow(); !errorsbreaker.ErrCallPanicked.In(err) || !strings.Contains(err.Error(), "boom") {
+		t.FailNow()
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if breaker.Do(func() error { return nil }) != nil || breaker.State() != errorsbreaker.StateClosed {
+		t.FailNow()
+	}
+}
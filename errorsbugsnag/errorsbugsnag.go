@@ -0,0 +1,198 @@
+// Package errorsbugsnag implements functions to report errors to Bugsnag.
+package errorsbugsnag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/neoxelox/errors"
+)
+
+const _DEFAULT_ENDPOINT = "https://notify.bugsnag.com"
+
+// ErrNotify is raised when an event cannot be delivered to Bugsnag.
+var ErrNotify = errors.New("cannot notify Bugsnag")
+
+// StackFrame represents a frame of a Bugsnag exception.
+// nolint:tagliatelle
+type StackFrame struct {
+	File       string `json:"file"`
+	LineNumber int    `json:"lineNumber"`
+	Method     string `json:"method"`
+}
+
+// Exception represents a Bugsnag exception.
+// nolint:tagliatelle
+type Exception struct {
+	ErrorClass string       `json:"errorClass"`
+	Message    string       `json:"message"`
+	Stacktrace []StackFrame `json:"stacktrace"`
+	Type       string       `json:"type"`
+}
+
+// Event represents a Bugsnag event following the Error Reporting API.
+// nolint:tagliatelle
+type Event struct {
+	Exceptions     []Exception               `json:"exceptions"`
+	Severity       string                    `json:"severity"`
+	SeverityReason map[string]string         `json:"severityReason"`
+	Unhandled      bool                      `json:"unhandled"`
+	GroupingHash   string                    `json:"groupingHash,omitempty"`
+	MetaData       map[string]map[string]any `json:"metaData,omitempty"`
+	App            map[string]string         `json:"app,omitempty"`
+}
+
+// NewEvent converts an error chain into a Bugsnag event, with the error class from
+// the types, the grouping hash from the stable hash and the extra and tags as
+// metadata tabs.
+func NewEvent(err error) Event {
+	event := Event{
+		Exceptions:     []Exception{},
+		Severity:       "error",
+		SeverityReason: map[string]string{"type": "handledError"},
+		Unhandled:      false,
+		MetaData:       map[string]map[string]any{},
+	}
+
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		event.GroupingHash = rerr.Hash()
+		if rerr.Level() == errors.LevelWarning {
+			event.Severity = "warning"
+		}
+	}
+
+	for cause := err; cause != nil; {
+		var cerr *errors.Error
+
+		switch typed := cause.(type) {
+		case *errors.Error:
+			cerr = typed
+		case errors.Error:
+			cerr = &typed
+		}
+
+		if cerr == nil {
+			event.Exceptions = append(event.Exceptions, Exception{
+				ErrorClass: strings.TrimPrefix(reflect.TypeOf(cause).String(), "*"),
+				Message:    cause.Error(),
+				Stacktrace: []StackFrame{},
+				Type:       "go",
+			})
+
+			break
+		}
+
+		stacktrace := make([]StackFrame, 0, len(cerr.StackTrace()))
+		for _, frame := range cerr.StackTrace() {
+			stacktrace = append(stacktrace, StackFrame{
+				File:       frame.File,
+				LineNumber: frame.Line,
+				Method:     frame.Function,
+			})
+		}
+
+		event.Exceptions = append(event.Exceptions, Exception{
+			ErrorClass: cerr.Kind(),
+			Message:    cerr.Message(),
+			Stacktrace: stacktrace,
+			Type:       "go",
+		})
+
+		addMetaData(event.MetaData, "extra", cerr.Extras())
+
+		tags := make(map[string]any, len(cerr.GetTags()))
+		for key, value := range cerr.GetTags() {
+			tags[key] = value
+		}
+
+		addMetaData(event.MetaData, "tags", tags)
+
+		cause = cerr.Unwrap()
+	}
+
+	return event
+}
+
+// addMetaData adds the values to a metadata tab, the outermost errors winning.
+func addMetaData(metaData map[string]map[string]any, tab string, values map[string]any) {
+	if len(values) == 0 {
+		return
+	}
+
+	if metaData[tab] == nil {
+		metaData[tab] = make(map[string]any, len(values))
+	}
+
+	for key, value := range values {
+		if _, ok := metaData[tab][key]; !ok {
+			metaData[tab][key] = value
+		}
+	}
+}
+
+// Notifier reports errors to Bugsnag through its Error Reporting API.
+type Notifier struct {
+	APIKey       string
+	ReleaseStage string
+	Endpoint     string
+	Client       *http.Client
+}
+
+// Notify reports an error to Bugsnag.
+func (self Notifier) Notify(ctx context.Context, err error) error {
+	event := NewEvent(err)
+	if self.ReleaseStage != "" {
+		event.App = map[string]string{"releaseStage": self.ReleaseStage}
+	}
+
+	payload, perr := json.Marshal(map[string]any{
+		"apiKey":         self.APIKey,
+		"payloadVersion": "5",
+		"notifier": map[string]string{
+			"name":    "neoxelox/errors",
+			"version": "1.0.0",
+			"url":     "https://github.com/neoxelox/errors",
+		},
+		"events": []Event{event},
+	})
+	if perr != nil {
+		return ErrNotify.Raise().Cause(perr)
+	}
+
+	endpoint := self.Endpoint
+	if endpoint == "" {
+		endpoint = _DEFAULT_ENDPOINT
+	}
+
+	request, rerr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if rerr != nil {
+		return ErrNotify.Raise().Cause(rerr)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Bugsnag-Api-Key", self.APIKey)
+	request.Header.Set("Bugsnag-Payload-Version", "5")
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, rerr := client.Do(request)
+	if rerr != nil {
+		return ErrNotify.Raise().Cause(rerr)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return ErrNotify.Raise().With("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
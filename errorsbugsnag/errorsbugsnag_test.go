@@ -0,0 +1,51 @@
+package errorsbugsnag_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsbugsnag"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrCannotDeposit = errors.New("cannot deposit")
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	var payload struct {
+		Events []errorsbugsnag.Event `json:"events"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer server.Close()
+
+	err := ErrCannotDeposit.Raise().Tags(map[string]any{"apiVersion": 2}).
+		Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700}))
+
+	notifier := errorsbugsnag.Notifier{APIKey: "key", Endpoint: server.URL}
+	if notifier.Notify(context.Background(), err) != nil {
+		t.FailNow()
+	}
+
+	if len(payload.Events) != 1 || len(payload.Events[0].Exceptions) != 2 {
+		t.FailNow()
+	}
+
+	event := payload.Events[0]
+	if event.Exceptions[1].ErrorClass != "user %s not found" || event.GroupingHash != err.Hash() {
+		t.FailNow()
+	}
+
+	if event.MetaData["extra"]["userID"] != 310700.0 || event.MetaData["tags"]["apiVersion"] != "2" {
+		t.FailNow()
+	}
+}
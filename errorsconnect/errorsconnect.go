@@ -0,0 +1,238 @@
+// Package errorsconnect implements functions to convert errors from and to Connect errors.
+package errorsconnect
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sync"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/neoxelox/errors"
+)
+
+var _registry = struct {
+	sync.RWMutex
+	templates []errors.Template
+	codes     []connect.Code
+}{
+	templates: []errors.Template{
+		errors.NotFound,
+		errors.Conflict,
+		errors.Unauthorized,
+		errors.InvalidArgument,
+		errors.ResourceExhausted,
+		errors.Internal,
+	},
+	codes: []connect.Code{
+		connect.CodeNotFound,
+		connect.CodeAlreadyExists,
+		connect.CodeUnauthenticated,
+		connect.CodeInvalidArgument,
+		connect.CodeResourceExhausted,
+		connect.CodeInternal,
+	},
+}
+
+// Register maps the Error's type to a Connect code, both for converting raised
+// errors into Connect errors and for reconstructing them on the client. The
+// canonical Errors are registered by default.
+func Register(template errors.Template, code connect.Code) {
+	_registry.Lock()
+	defer _registry.Unlock()
+
+	_registry.templates = append(_registry.templates, template)
+	_registry.codes = append(_registry.codes, code)
+}
+
+func lookup(match func(template errors.Template) bool) (errors.Template, connect.Code, bool) {
+	_registry.RLock()
+	defer _registry.RUnlock()
+
+	for i, template := range _registry.templates {
+		if match(template) {
+			return template, _registry.codes[i], true
+		}
+	}
+
+	return errors.Template{}, connect.CodeUnknown, false
+}
+
+// publicError exposes the public message of an Error to the Connect clients while
+// keeping it unwrappable within the service.
+type publicError struct {
+	error
+	message string
+}
+
+// Error implements the Error interface.
+func (self publicError) Error() string {
+	return self.message
+}
+
+// Unwrap returns the Error.
+func (self publicError) Unwrap() error {
+	return self.error
+}
+
+// ToConnect converts an error into a Connect error with the public message of the
+// Error (see errors.Error.PublicMessage) and the code registered for the first
+// matching type of its chain (default is unknown), attaching its code, type and
+// package as an error detail, followed by the protobuf detail messages of the
+// chain (see errors.Error.Detail). The extra information is only attached if message
+// masking is disabled (see errors.SetMessageMasking). Errors not raised by the
+// errors package are converted with their messages hidden.
+func ToConnect(err error) *connect.Error {
+	if err == nil {
+		return nil
+	}
+
+	var cerr *connect.Error
+	if goerrors.As(err, &cerr) {
+		return cerr
+	}
+
+	var rerr errors.Error
+	if !goerrors.As(err, &rerr) {
+		return connect.NewError(connect.CodeUnknown, publicError{error: err, message: connect.CodeUnknown.String()})
+	}
+
+	_, code, ok := lookup(func(template errors.Template) bool { return template.Is(rerr) })
+	if !ok {
+		_, code, _ = lookup(func(template errors.Template) bool { return template.In(rerr) })
+	}
+
+	cerr = connect.NewError(code, publicError{error: err, message: rerr.PublicMessage()})
+
+	extra := make(map[string]any)
+	if !errors.MessageMasking() {
+		for key, value := range rerr.Extras() {
+			extra[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	detail, derr := structpb.NewStruct(map[string]any{
+		"code":    rerr.Code(),
+		"kind":    rerr.Kind(),
+		"module":  rerr.Module(),
+		"message": rerr.PublicMessage(),
+		"extra":   extra,
+	})
+	if derr != nil {
+		return cerr
+	}
+
+	if edetail, derr := connect.NewErrorDetail(detail); derr == nil {
+		cerr.AddDetail(edetail)
+	}
+
+	for _, message := range rerr.AllDetails() {
+		pmessage, ok := message.(proto.Message)
+		if !ok {
+			continue
+		}
+
+		if edetail, derr := connect.NewErrorDetail(pmessage); derr == nil {
+			cerr.AddDetail(edetail)
+		}
+	}
+
+	return cerr
+}
+
+// FromConnect reconstructs a raised Error from a Connect error carrying the detail
+// attached by ToConnect whose type has been registered, or declared in the
+// process (see errors.Lookup), along with the other detail messages, otherwise
+// the error is returned as is.
+func FromConnect(err error) error {
+	var cerr *connect.Error
+	if !goerrors.As(err, &cerr) {
+		return err
+	}
+
+	var rerr *errors.Error
+	var messages []proto.Message
+
+	for _, edetail := range cerr.Details() {
+		value, derr := edetail.Value()
+		if derr != nil {
+			continue
+		}
+
+		detail, ok := value.(*structpb.Struct)
+		if !ok || rerr != nil {
+			messages = append(messages, value)
+			continue
+		}
+
+		fields := detail.AsMap()
+		kind, _ := fields["kind"].(string)
+		module, _ := fields["module"].(string)
+		message, _ := fields["message"].(string)
+		extra, _ := fields["extra"].(map[string]any)
+
+		template, _, ok := lookup(func(template errors.Template) bool {
+			return template.Kind() == kind && template.Module() == module
+		})
+		if !ok {
+			template, ok = errors.Lookup(module, kind)
+		}
+
+		if !ok {
+			continue
+		}
+
+		rerr = template.RaiseMessage(message).Extra(extra).Skip(1)
+	}
+
+	if rerr == nil {
+		return err
+	}
+
+	for _, message := range messages {
+		rerr.Detail(message)
+	}
+
+	return rerr
+}
+
+type interceptor struct{}
+
+// NewInterceptor creates a Connect interceptor that converts the errors returned by
+// handlers into Connect errors and reconstructs them on the client.
+func NewInterceptor() connect.Interceptor {
+	return &interceptor{}
+}
+
+func (self *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, request connect.AnyRequest) (connect.AnyResponse, error) {
+		response, err := next(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+
+		if request.Spec().IsClient {
+			return response, FromConnect(err)
+		}
+
+		return response, ToConnect(err)
+	}
+}
+
+func (self *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (self *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		err := next(ctx, conn)
+		if err == nil {
+			return nil
+		}
+
+		return ToConnect(err)
+	}
+}
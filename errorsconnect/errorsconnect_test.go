@@ -0,0 +1,126 @@
+package errorsconnect_test
+
+import (
+	goerrors "errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsconnect"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrAccountLocked = errors.New("account locked")
+)
+
+func init() {
+	errorsconnect.Register(ErrUserNotFound, connect.CodeNotFound)
+}
+
+// nolint:paralleltest
+func TestRoundTrip(t *testing.T) {
+	defer errors.SetMessageMasking(true)
+
+	err := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700}).
+		Cause(goerrors.New("dial tcp 10.0.0.7:5432: refused"))
+
+	cerr := errorsconnect.ToConnect(err)
+	if cerr.Code() != connect.CodeNotFound || len(cerr.Details()) != 1 || cerr.Message() != "user Alex not found" {
+		t.FailNow()
+	}
+
+	rerr, ok := errorsconnect.FromConnect(cerr).(*errors.Error)
+	if !ok || !ErrUserNotFound.Is(rerr) || rerr.Error() != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if _, ok := errors.GetExtraAs[string](rerr, "userID"); ok {
+		t.FailNow()
+	}
+
+	errors.SetMessageMasking(false)
+
+	cerr = errorsconnect.ToConnect(err)
+	if cerr.Message() != "user Alex not found: dial tcp 10.0.0.7:5432: refused" {
+		t.FailNow()
+	}
+
+	userID, ok := errors.GetExtraAs[string](errorsconnect.FromConnect(cerr), "userID")
+	if !ok || userID != "310700" {
+		t.FailNow()
+	}
+
+	if errorsconnect.ToConnect(goerrors.New("dial tcp 10.0.0.7:5432: refused")).Message() != "unknown" {
+		t.FailNow()
+	}
+}
+
+func TestDeclared(t *testing.T) {
+	t.Parallel()
+
+	rerr, ok := errorsconnect.FromConnect(errorsconnect.ToConnect(ErrAccountLocked.Raise())).(*errors.Error)
+	if !ok || !ErrAccountLocked.Is(rerr) {
+		t.FailNow()
+	}
+}
+
+func TestDetails(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Detail(&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND", Domain: "example.com"})
+
+	cerr := errorsconnect.ToConnect(err)
+	if len(cerr.Details()) != 2 {
+		t.FailNow()
+	}
+
+	rerr, ok := errorsconnect.FromConnect(cerr).(*errors.Error)
+	if !ok || !ErrUserNotFound.Is(rerr) || len(rerr.Details()) != 1 {
+		t.FailNow()
+	}
+
+	if info, ok := rerr.Details()[0].(*errdetails.ErrorInfo); !ok || info.GetReason() != "USER_NOT_FOUND" {
+		t.FailNow()
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Cause(errors.Conflict.Raise())
+	if errorsconnect.ToConnect(errors.Conflict.Raise()).Code() != connect.CodeAlreadyExists {
+		t.FailNow()
+	}
+
+	if errorsconnect.ToConnect(err).Code() != connect.CodeNotFound {
+		t.FailNow()
+	}
+
+	rerr := errorsconnect.FromConnect(errorsconnect.ToConnect(errors.Unauthorized.Raise()))
+	if !errors.Unauthorized.Is(rerr) {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestCodePrefix(t *testing.T) {
+	defer errors.SetCodePrefix(false)
+
+	errors.SetCodePrefix(true)
+
+	template := errors.NewWithOptions("user %s banned", errors.NewOptions{Code: "USER_BANNED"})
+	errorsconnect.Register(template, connect.CodePermissionDenied)
+
+	cerr := errorsconnect.ToConnect(template.Raise("Alex"))
+	if cerr.Message() != "user Alex banned" {
+		t.FailNow()
+	}
+
+	rerr, ok := errorsconnect.FromConnect(cerr).(*errors.Error)
+	if !ok || !template.Is(rerr) || rerr.Error() != "[USER_BANNED] user Alex banned" {
+		t.FailNow()
+	}
+}
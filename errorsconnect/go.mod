@@ -0,0 +1,16 @@
+module github.com/neoxelox/errors/errorsconnect
+
+go 1.21.1
+
+require (
+	connectrpc.com/connect v1.16.1
+	github.com/neoxelox/errors v0.0.0-20261016142157-8062b4b23cef
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240513163218-0867130af1f8
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/getsentry/sentry-go v0.28.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
@@ -0,0 +1,193 @@
+// Package errorsdeadletter implements a sink of errors publishing them, along
+// with a reference to the message whose processing failed, to a dead-letter
+// topic or subject of a message broker such as Kafka or NATS, to be consumed by
+// triage tooling.
+//
+// The package doesn't depend on any broker client. A Publisher is a thin adapter,
+// such as for github.com/segmentio/kafka-go:
+//
+//	errorsdeadletter.PublisherFunc(func(ctx context.Context, topic string, key []byte, value []byte,
+//		headers map[string]string) error {
+//		message := kafka.Message{Topic: topic, Key: key, Value: value}
+//		for name, value := range headers {
+//			message.Headers = append(message.Headers, kafka.Header{Key: name, Value: []byte(value)})
+//		}
+//
+//		return writer.WriteMessages(ctx, message)
+//	})
+//
+// or for github.com/nats-io/nats.go:
+//
+//	errorsdeadletter.PublisherFunc(func(ctx context.Context, subject string, key []byte, value []byte,
+//		headers map[string]string) error {
+//		message := nats.NewMsg(subject)
+//		message.Data = value
+//		for name, value := range headers {
+//			message.Header.Set(name, value)
+//		}
+//
+//		return conn.PublishMsg(message)
+//	})
+package errorsdeadletter
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_DEFAULT_TIMEOUT = 5 * time.Second
+	_MESSAGE_EXTRA   = "deadletter.message"
+)
+
+// ErrPublish is raised when an error cannot be published to the dead-letter topic.
+var ErrPublish = errors.New("cannot publish dead letter")
+
+// Publisher publishes messages to a topic or subject of a message broker.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, key []byte, value []byte, headers map[string]string) error
+}
+
+// PublisherFunc adapts a function into a Publisher.
+type PublisherFunc func(ctx context.Context, topic string, key []byte, value []byte, headers map[string]string) error
+
+// Publish implements the Publisher interface.
+func (self PublisherFunc) Publish(ctx context.Context, topic string, key []byte, value []byte,
+	headers map[string]string,
+) error {
+	return self(ctx, topic, key, value, headers)
+}
+
+// Message references the message whose processing failed.
+type Message struct {
+	Topic     string            `json:"topic"`
+	Partition int32             `json:"partition,omitempty"`
+	Offset    int64             `json:"offset,omitempty"`
+	Key       []byte            `json:"key,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	// Payload is the original payload of the message, if it must be kept along
+	// with the error instead of being referenced by its offset.
+	Payload []byte `json:"payload,omitempty"`
+}
+
+// WithMessage records the reference to the message whose processing failed into
+// the raised Error, to be published along with it.
+func WithMessage(err *errors.Error, message Message) *errors.Error {
+	return err.Extra(map[string]any{_MESSAGE_EXTRA: message})
+}
+
+// Envelope represents the JSON value of the dead letters.
+type Envelope struct {
+	// Error is the verbose structured JSON encoding of the error.
+	Error json.RawMessage `json:"error"`
+	// Hash is the stable hash of the error, to group its occurrences.
+	Hash string `json:"hash,omitempty"`
+	// Message is the message whose processing failed, if recorded with WithMessage.
+	Message *Message `json:"message,omitempty"`
+	// FailedAt is the time the dead letter was published.
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// Options represents the options of a DeadLetter.
+type Options struct {
+	// MinLevel is the minimum level of the published errors (default is error).
+	MinLevel errors.Level
+	// Timeout is the maximum time to publish a dead letter when used as a sink
+	// (default is 5 seconds).
+	Timeout time.Duration
+}
+
+// DeadLetter publishes errors to a dead-letter topic, usable as a sink of
+// errors.Report.
+type DeadLetter struct {
+	publisher Publisher
+	topic     string
+	options   Options
+}
+
+// New creates a new DeadLetter publishing to the topic or subject through the
+// publisher with optional options.
+func New(publisher Publisher, topic string, options ...Options) *DeadLetter {
+	_options := Options{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.Timeout <= 0 {
+		_options.Timeout = _DEFAULT_TIMEOUT
+	}
+
+	return &DeadLetter{
+		publisher: publisher,
+		topic:     topic,
+		options:   _options,
+	}
+}
+
+// Publish publishes the error as a JSON Envelope if its level reaches the minimum
+// level, keyed by the key of the failed message or the error's hash otherwise,
+// with its type, code and level as headers. Foreign errors have the error level.
+func (self *DeadLetter) Publish(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	headers := map[string]string{"content-type": "application/json"}
+	envelope := Envelope{FailedAt: time.Now().UTC()}
+
+	var cerr errors.Error
+	if goerrors.As(err, &cerr) {
+		if cerr.Level() < self.options.MinLevel {
+			return nil
+		}
+
+		envelope.Hash = cerr.Hash()
+		headers["error-kind"] = cerr.Kind()
+		headers["error-level"] = cerr.Level().String()
+
+		if cerr.Code() != "" {
+			headers["error-code"] = cerr.Code()
+		}
+	} else if errors.LevelError < self.options.MinLevel {
+		return nil
+	}
+
+	if message, ok := errors.GetExtraAs[Message](err, _MESSAGE_EXTRA); ok {
+		envelope.Message = &message
+	}
+
+	encoded, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if jerr != nil {
+		return ErrPublish.Raise().Cause(jerr)
+	}
+
+	envelope.Error = encoded
+
+	value, jerr := json.Marshal(envelope)
+	if jerr != nil {
+		return ErrPublish.Raise().Cause(jerr)
+	}
+
+	key := []byte(envelope.Hash)
+	if envelope.Message != nil && len(envelope.Message.Key) > 0 {
+		key = envelope.Message.Key
+	}
+
+	if perr := self.publisher.Publish(ctx, self.topic, key, value, headers); perr != nil {
+		return ErrPublish.Raise().Extra(map[string]any{"topic": self.topic}).Cause(perr)
+	}
+
+	return nil
+}
+
+// Write implements the errors.Sink interface.
+func (self *DeadLetter) Write(err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), self.options.Timeout)
+	defer cancel()
+
+	return self.Publish(ctx, err)
+}
@@ -0,0 +1,86 @@
+package errorsdeadletter_test
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsdeadletter"
+)
+
+var (
+	ErrCannotProcess = errors.NewWithOptions("cannot process order", errors.NewOptions{Code: "ORDER"})
+	ErrSkipped       = errors.NewWarning("order skipped")
+)
+
+type published struct {
+	topic   string
+	key     string
+	value   []byte
+	headers map[string]string
+}
+
+func TestDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	var letters []published
+
+	deadLetter := errorsdeadletter.New(errorsdeadletter.PublisherFunc(
+		func(_ context.Context, topic string, key []byte, value []byte, headers map[string]string) error {
+			letters = append(letters, published{topic: topic, key: string(key), value: value, headers: headers})
+			return nil
+		}), "orders.dlq")
+
+	err := ErrCannotProcess.Raise().Extra(map[string]any{"order_id": 42})
+	errorsdeadletter.WithMessage(err, errorsdeadletter.Message{Topic: "orders", Partition: 3, Offset: 1042, Key: []byte("42")})
+
+	if deadLetter.Write(err) != nil || deadLetter.Write(ErrSkipped.Raise()) != nil || len(letters) != 1 {
+		t.FailNow()
+	}
+
+	letter := letters[0]
+	if letter.topic != "orders.dlq" || letter.key != "42" || letter.headers["error-code"] != "ORDER" {
+		t.FailNow()
+	}
+
+	var envelope struct {
+		Error struct {
+			Code  string         `json:"code"`
+			Stack []any          `json:"stack"`
+			Extra map[string]any `json:"extra"`
+		} `json:"error"`
+		Hash    string                   `json:"hash"`
+		Message errorsdeadletter.Message `json:"message"`
+	}
+
+	if json.Unmarshal(letter.value, &envelope) != nil {
+		t.FailNow()
+	}
+
+	if envelope.Error.Code != "ORDER" || len(envelope.Error.Stack) == 0 || envelope.Error.Extra["order_id"] != 42.0 {
+		t.FailNow()
+	}
+
+	if envelope.Hash != err.Hash() || envelope.Message.Offset != 1042 || envelope.Message.Topic != "orders" {
+		t.FailNow()
+	}
+
+	if deadLetter.Write(goerrors.New("foreign")) != nil || len(letters) != 2 || letters[1].key != "" {
+		t.FailNow()
+	}
+}
+
+func TestDeadLetterFailure(t *testing.T) {
+	t.Parallel()
+
+	deadLetter := errorsdeadletter.New(errorsdeadletter.PublisherFunc(
+		func(context.Context, string, []byte, []byte, map[string]string) error {
+			return goerrors.New("broker unavailable")
+		}), "orders.dlq")
+
+	if !errorsdeadletter.ErrPublish.Is(deadLetter.Write(ErrCannotProcess.Raise())) {
+		t.FailNow()
+	}
+}
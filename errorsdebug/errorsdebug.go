@@ -0,0 +1,159 @@
+// Package errorsdebug implements a debug endpoint and an expvar variable exposing
+// the error counters and the last reports of a live instance.
+package errorsdebug
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"expvar"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const _DEFAULT_CAPACITY = 50
+
+const _DEFAULT_WINDOW = time.Hour
+
+// Entry represents a recorded error report.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Module  string    `json:"module"`
+	Message string    `json:"message"`
+	Report  string    `json:"report"`
+}
+
+// Snapshot represents the error counters within a window of time and the last
+// recorded reports, most recent first.
+type Snapshot struct {
+	Window string             `json:"window"`
+	Counts []errors.KindCount `json:"counts"`
+	Recent []Entry            `json:"recent"`
+}
+
+var _recent = struct {
+	sync.Mutex
+	entries  []Entry
+	next     int
+	capacity int
+}{
+	capacity: _DEFAULT_CAPACITY,
+}
+
+// SetCapacity sets the number of last reports kept by Record (default is 50).
+func SetCapacity(capacity int) {
+	_recent.Lock()
+	defer _recent.Unlock()
+
+	_recent.entries = nil
+	_recent.next = 0
+	_recent.capacity = capacity
+}
+
+// Record keeps the report of the error in a ring buffer of the last reports,
+// hiding the values of its extra information.
+func Record(err error) {
+	if err == nil {
+		return
+	}
+
+	entry := Entry{
+		Time:    time.Now(),
+		Message: err.Error(),
+	}
+
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		entry.Kind = rerr.Kind()
+		entry.Module = rerr.Module()
+		entry.Report = rerr.Report(errors.ReportOptions{All: true, Redact: true})
+	} else {
+		entry.Kind = strings.TrimPrefix(reflect.TypeOf(err).String(), "*")
+		entry.Report = err.Error()
+	}
+
+	_recent.Lock()
+	defer _recent.Unlock()
+
+	if _recent.capacity <= 0 {
+		return
+	}
+
+	if len(_recent.entries) < _recent.capacity {
+		_recent.entries = append(_recent.entries, entry)
+	} else {
+		_recent.entries[_recent.next] = entry
+	}
+
+	_recent.next = (_recent.next + 1) % _recent.capacity
+}
+
+// Recent returns the last recorded reports, most recent first.
+func Recent() []Entry {
+	_recent.Lock()
+	defer _recent.Unlock()
+
+	entries := make([]Entry, 0, len(_recent.entries))
+	for i := 1; i <= len(_recent.entries); i++ {
+		entries = append(entries, _recent.entries[(_recent.next-i+len(_recent.entries))%len(_recent.entries)])
+	}
+
+	return entries
+}
+
+// Take returns the error counters within the window of time (default is an hour)
+// and the last recorded reports.
+func Take(window ...time.Duration) Snapshot {
+	_window := _DEFAULT_WINDOW
+	if len(window) > 0 {
+		_window = window[0]
+	}
+
+	return Snapshot{
+		Window: _window.String(),
+		Counts: errors.Stats().Top(0, _window),
+		Recent: Recent(),
+	}
+}
+
+var _publish sync.Once
+
+// Publish exposes the snapshot of the last hour as the "errors" expvar variable,
+// served at /debug/vars along with the rest of expvar variables, enabling the
+// error counters (see errors.SetStats).
+func Publish() {
+	errors.SetStats(true)
+
+	_publish.Do(func() {
+		expvar.Publish("errors", expvar.Func(func() any { return Take() }))
+	})
+}
+
+// Handler creates an HTTP handler serving the snapshot as JSON, within the
+// window of time of the "window" query parameter (default is an hour), so it
+// can be mounted at /debug/errors on admin servers. It enables the error counters
+// (see errors.SetStats).
+func Handler() http.Handler {
+	errors.SetStats(true)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		window := _DEFAULT_WINDOW
+		if value := r.URL.Query().Get("window"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, "invalid window", http.StatusBadRequest)
+				return
+			}
+
+			window = parsed
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Take(window))
+	})
+}
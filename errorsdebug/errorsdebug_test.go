@@ -0,0 +1,54 @@
+package errorsdebug_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsdebug"
+)
+
+var ErrPaymentFailed = errors.New("payment of %d failed")
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := errorsdebug.Handler()
+
+	errorsdebug.Record(ErrPaymentFailed.Raise(42).Extra(map[string]any{"card": "4242424242424242"}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/errors?window=5m", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.FailNow()
+	}
+
+	var snapshot errorsdebug.Snapshot
+	if json.Unmarshal(recorder.Body.Bytes(), &snapshot) != nil {
+		t.FailNow()
+	}
+
+	if snapshot.Window != "5m0s" || len(snapshot.Counts) == 0 || len(snapshot.Recent) == 0 {
+		t.FailNow()
+	}
+
+	entry := snapshot.Recent[0]
+	if entry.Kind != ErrPaymentFailed.Kind() || entry.Message != "payment of 42 failed" {
+		t.FailNow()
+	}
+
+	if strings.Contains(entry.Report, "4242424242424242") || !strings.Contains(entry.Report, "card=[REDACTED]") {
+		t.FailNow()
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/errors?window=x", nil))
+
+	if recorder.Code != http.StatusBadRequest {
+		t.FailNow()
+	}
+}
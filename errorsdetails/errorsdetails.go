@@ -0,0 +1,127 @@
+// Package errorsdetails implements the errors.DetailCodec of the protobuf detail
+// messages of the Google API error model, such as the google.rpc BadRequest,
+// RetryInfo or ErrorInfo, so the core module doesn't depend on protobuf:
+//
+//	errors.SetDetailCodec(errorsdetails.Codec{})
+//	...
+//	err := ErrQuotaExceeded.Raise("requests").
+//		Detail(&errdetails.RetryInfo{RetryDelay: durationpb.New(5 * time.Second)})
+package errorsdetails
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/neoxelox/errors"
+)
+
+// ErrNotProto is returned by the Codec when encoding detail messages that are not
+// protobuf messages for the gob encoding.
+var ErrNotProto = errors.New("detail message of type %T is not a protobuf message", false)
+
+// Codec is the errors.DetailCodec of the protobuf detail messages. The detail
+// messages that are not protobuf messages are rendered with their default format,
+// encoded as JSON and skipped in the gob encoding.
+type Codec struct{}
+
+// String renders the detail message readably, with dedicated renderings for the
+// most common google.rpc details and the compact text format otherwise.
+func (self Codec) String(message any) string {
+	switch detail := message.(type) {
+	case *errdetails.BadRequest:
+		violations := make([]string, 0, len(detail.GetFieldViolations()))
+		for _, violation := range detail.GetFieldViolations() {
+			violations = append(violations, violation.GetField()+": "+violation.GetDescription())
+		}
+
+		return "Bad request: " + strings.Join(violations, "; ")
+	case *errdetails.RetryInfo:
+		return "Retry after: " + detail.GetRetryDelay().AsDuration().String()
+	case *errdetails.ErrorInfo:
+		keys := make([]string, 0, len(detail.GetMetadata()))
+		for key := range detail.GetMetadata() {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		info := "Error info: " + detail.GetReason() + " (" + detail.GetDomain() + ")"
+		for _, key := range keys {
+			info += " " + key + "=" + detail.GetMetadata()[key]
+		}
+
+		return info
+	case proto.Message:
+		text := prototext.MarshalOptions{}.Format(detail)
+		return string(detail.ProtoReflect().Descriptor().FullName()) + ": " + strings.Join(strings.Fields(text), " ")
+	default:
+		return fmt.Sprintf("%v", message)
+	}
+}
+
+// Encode serializes the detail message as a google.protobuf.Any message, so it
+// can be restored without knowing its type beforehand.
+func (self Codec) Encode(message any) ([]byte, error) {
+	detail, ok := message.(proto.Message)
+	if !ok {
+		return nil, ErrNotProto.Raise(message)
+	}
+
+	wrapped, err := anypb.New(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(wrapped)
+}
+
+// Decode restores the detail message serialized by Encode. The messages whose
+// types are not linked into the binary are kept as google.protobuf.Any messages.
+func (self Codec) Decode(data []byte) (any, error) {
+	wrapped := &anypb.Any{}
+	if err := proto.Unmarshal(data, wrapped); err != nil {
+		return nil, err
+	}
+
+	message, err := wrapped.UnmarshalNew()
+	if err != nil {
+		return wrapped, nil // nolint:nilerr
+	}
+
+	return message, nil
+}
+
+// EncodeJSON encodes the detail message as a google.protobuf.Any message in the
+// protobuf JSON mapping, compacted so the encodings are byte-stable.
+func (self Codec) EncodeJSON(message any) ([]byte, error) {
+	detail, ok := message.(proto.Message)
+	if !ok {
+		return json.Marshal(message)
+	}
+
+	wrapped, err := anypb.New(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := protojson.Marshal(wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return nil, err
+	}
+
+	return compacted.Bytes(), nil
+}
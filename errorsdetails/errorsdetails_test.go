@@ -0,0 +1,103 @@
+package errorsdetails_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsdetails"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrQuotaExceeded = errors.New("quota of %s exceeded")
+)
+
+// nolint:paralleltest
+func TestCodec(t *testing.T) {
+	errors.SetDetailCodec(errorsdetails.Codec{})
+	defer errors.SetDetailCodec(nil)
+
+	cause := ErrUserNotFound.Raise("Alex").Detail(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "email", Description: "must be valid"},
+			{Field: "age", Description: "must be positive"},
+		},
+	})
+
+	err := ErrQuotaExceeded.Raise("requests").
+		Detail(&errdetails.RetryInfo{RetryDelay: durationpb.New(5 * time.Second)}).
+		Detail(&errdetails.ErrorInfo{
+			Reason:   "RATE_LIMIT_EXCEEDED",
+			Domain:   "example.com",
+			Metadata: map[string]string{"service": "billing", "limit": "100"},
+		}).
+		Detail(structpb.NewStringValue("custom")).
+		Cause(cause)
+
+	if len(err.Details()) != 3 || len(err.AllDetails()) != 4 {
+		t.FailNow()
+	}
+
+	if _, ok := err.AllDetails()[3].(*errdetails.BadRequest); !ok {
+		t.FailNow()
+	}
+
+	report := err.Report(errors.ReportOptions{All: true})
+
+	expected := []string{
+		"    Details:\n",
+		"      Retry after: 5s\n",
+		"      Error info: RATE_LIMIT_EXCEEDED (example.com) limit=100 service=billing\n",
+		// The text format inserts random spaces on purpose, so only its prefix is stable
+		"      google.protobuf.Value: string_value:",
+		"      Bad request: email: must be valid; age: must be positive\n",
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(report, line) {
+			t.FailNow()
+		}
+	}
+
+	if extra, ok := err.SentryReport().Extra["details"].([]string); !ok || len(extra) != 4 {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestCodecEncoding(t *testing.T) {
+	errors.SetDetailCodec(errorsdetails.Codec{})
+	defer errors.SetDetailCodec(nil)
+
+	err := ErrQuotaExceeded.Raise("requests").
+		Detail(&errdetails.RetryInfo{RetryDelay: durationpb.New(5 * time.Second)}).
+		Detail(map[string]string{"plain": "detail"}).
+		Cause(ErrUserNotFound.Raise("Alex").Detail(&errdetails.ErrorInfo{Reason: "USER_NOT_FOUND"}))
+
+	data, merr := err.MarshalBinary()
+	if merr != nil {
+		t.FailNow()
+	}
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || len(decoded.AllDetails()) != 2 {
+		t.FailNow()
+	}
+
+	if retry, ok := decoded.Details()[0].(*errdetails.RetryInfo); !ok || retry.GetRetryDelay().AsDuration() != 5*time.Second {
+		t.FailNow()
+	}
+
+	verbose, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if jerr != nil || !strings.Contains(string(verbose),
+		`"details":[{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"5s"},{"plain":"detail"}]`) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/neoxelox/errors/errorsdetails
+
+go 1.21.1
+
+require (
+	github.com/neoxelox/errors v0.0.0-20261016142157-8062b4b23cef
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240513163218-0867130af1f8
+	google.golang.org/protobuf v1.34.2
+)
+
+require (
+	github.com/getsentry/sentry-go v0.28.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
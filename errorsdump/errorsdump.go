@@ -0,0 +1,273 @@
+// Package errorsdump implements a sink that persists error reports to local files
+// as a last-resort audit trail, and a reader to re-ship them on recovery.
+package errorsdump
+
+import (
+	"bufio"
+	"encoding/json"
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const _ROTATED_LAYOUT = "20060102T150405.000000000"
+
+var (
+	// ErrDump is raised when a report cannot be persisted.
+	ErrDump = errors.New("cannot dump error report")
+	// ErrReplay is raised when the dumped reports cannot be replayed.
+	ErrReplay = errors.New("cannot replay dumped error reports")
+)
+
+// Record represents a persisted error report.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Module  string    `json:"module"`
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Report  string    `json:"report"`
+}
+
+// Options represents the options of a Dump.
+type Options struct {
+	// MaxSize rotates the file once it reaches that number of bytes (0 means
+	// unlimited).
+	MaxSize int64
+	// MaxAge rotates the file once it is that old (0 means unlimited).
+	MaxAge time.Duration
+	// Sync flushes each report to the disk before Write returns.
+	Sync bool
+}
+
+// Dump appends JSON reports, one per line, to a local file rotating it by size
+// and age. Rotated files are renamed with the rotation time as suffix.
+type Dump struct {
+	mutex   sync.Mutex
+	path    string
+	options Options
+	file    *os.File
+	size    int64
+	opened  time.Time
+}
+
+// Open opens or creates the dump file at path.
+func Open(path string, options Options) (*Dump, error) {
+	dump := &Dump{
+		path:    path,
+		options: options,
+	}
+
+	if err := dump.open(); err != nil {
+		return nil, err
+	}
+
+	return dump, nil
+}
+
+func (self *Dump) open() error {
+	// nolint:gosec
+	file, err := os.OpenFile(self.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return ErrDump.Raise().Cause(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return ErrDump.Raise().Cause(err)
+	}
+
+	self.file = file
+	self.size = info.Size()
+	self.opened = time.Now()
+
+	return nil
+}
+
+func (self *Dump) rotate() error {
+	if self.size == 0 {
+		return nil
+	}
+
+	if err := self.file.Close(); err != nil {
+		return ErrDump.Raise().Cause(err)
+	}
+
+	if err := os.Rename(self.path, self.path+"."+time.Now().UTC().Format(_ROTATED_LAYOUT)); err != nil {
+		// Reopens the current file so the next reports can still be written.
+		if oerr := self.open(); oerr != nil {
+			return oerr
+		}
+
+		return ErrDump.Raise().Cause(err)
+	}
+
+	return self.open()
+}
+
+// Rotate renames the current file so its reports can be replayed.
+func (self *Dump) Rotate() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	return self.rotate()
+}
+
+// Write appends the report of all errors wrapped within the error to the file,
+// rotating it beforehand if needed.
+func (self *Dump) Write(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	record := Record{
+		Time:    time.Now(),
+		Message: err.Error(),
+		Report:  err.Error(),
+	}
+
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		record.Kind = rerr.Kind()
+		record.Module = rerr.Module()
+		record.Hash = rerr.Hash()
+		record.Report = rerr.Report(errors.ReportOptions{All: true})
+	}
+
+	line, merr := json.Marshal(record)
+	if merr != nil {
+		return ErrDump.Raise().Cause(merr)
+	}
+
+	line = append(line, '\n')
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if (self.options.MaxSize > 0 && self.size+int64(len(line)) > self.options.MaxSize) ||
+		(self.options.MaxAge > 0 && time.Since(self.opened) >= self.options.MaxAge) {
+		if rerr := self.rotate(); rerr != nil {
+			return rerr
+		}
+	}
+
+	written, werr := self.file.Write(line)
+	self.size += int64(written)
+	if werr != nil {
+		return ErrDump.Raise().Cause(werr)
+	}
+
+	if self.options.Sync {
+		if serr := self.file.Sync(); serr != nil {
+			return ErrDump.Raise().Cause(serr)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the file.
+func (self *Dump) Close() error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if err := self.file.Close(); err != nil {
+		return ErrDump.Raise().Cause(err)
+	}
+
+	return nil
+}
+
+// rotatedFiles returns the rotated files of the dump at path, oldest first. Other
+// files with the same prefix, such as backups, are skipped.
+func rotatedFiles(path string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := filepath.Base(path) + "."
+	times := make(map[string]time.Time)
+
+	var rotated []string
+
+	for _, entry := range entries {
+		suffix, found := strings.CutPrefix(entry.Name(), prefix)
+		if !found || entry.IsDir() {
+			continue
+		}
+
+		rotatedAt, err := time.Parse(_ROTATED_LAYOUT, suffix)
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Join(filepath.Dir(path), entry.Name())
+		times[name] = rotatedAt
+		rotated = append(rotated, name)
+	}
+
+	sort.Slice(rotated, func(i, j int) bool {
+		return times[rotated[i]].Before(times[rotated[j]])
+	})
+
+	return rotated, nil
+}
+
+// Replay calls ship with every record of the rotated files of the dump at path,
+// oldest first, removing each file once all its records have been shipped. It
+// stops at the first error, so records may be shipped more than once.
+func Replay(path string, ship func(Record) error) error {
+	rotated, err := rotatedFiles(path)
+	if err != nil {
+		return ErrReplay.Raise().Cause(err)
+	}
+
+	for _, name := range rotated {
+		if err := replayFile(name, ship); err != nil {
+			return err
+		}
+
+		if err := os.Remove(name); err != nil {
+			return ErrReplay.Raise().Cause(err)
+		}
+	}
+
+	return nil
+}
+
+func replayFile(name string, ship func(Record) error) error {
+	// nolint:gosec
+	file, err := os.Open(name)
+	if err != nil {
+		return ErrReplay.Raise().Cause(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), 16<<20)
+
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+
+		if err := ship(record); err != nil {
+			return ErrReplay.Raise().Cause(err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return ErrReplay.Raise().Cause(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package errorsdump_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsdump"
+)
+
+var ErrPaymentFailed = errors.New("payment of %d failed")
+
+func TestDump(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "errors.log")
+
+	dump, err := errorsdump.Open(path, errorsdump.Options{MaxSize: 1, MaxAge: time.Hour, Sync: true})
+	if err != nil {
+		t.FailNow()
+	}
+
+	for i := 1; i <= 3; i++ {
+		if dump.Write(ErrPaymentFailed.Raise(i)) != nil {
+			t.FailNow()
+		}
+	}
+
+	if dump.Rotate() != nil || dump.Close() != nil {
+		t.FailNow()
+	}
+
+	shipped := []errorsdump.Record{}
+	err = errorsdump.Replay(path, func(record errorsdump.Record) error {
+		shipped = append(shipped, record)
+		return nil
+	})
+	if err != nil || len(shipped) != 3 {
+		t.FailNow()
+	}
+
+	if shipped[0].Message != "payment of 1 failed" || shipped[2].Kind != ErrPaymentFailed.Kind() {
+		t.FailNow()
+	}
+
+	if rotated, _ := filepath.Glob(path + ".*"); len(rotated) != 0 {
+		t.FailNow()
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Size() != 0 {
+		t.FailNow()
+	}
+}
+
+func TestDumpRotateFailure(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "errors.log")
+
+	dump, err := errorsdump.Open(path, errorsdump.Options{})
+	if err != nil {
+		t.FailNow()
+	}
+	defer dump.Close()
+
+	if dump.Write(ErrPaymentFailed.Raise(1)) != nil || os.Remove(path) != nil {
+		t.FailNow()
+	}
+
+	if !errorsdump.ErrDump.Is(dump.Rotate()) {
+		t.FailNow()
+	}
+
+	if dump.Write(ErrPaymentFailed.Raise(2)) != nil {
+		t.FailNow()
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.FailNow()
+	}
+}
+
+func TestReplayUnrelatedFiles(t *testing.T) {
+	t.Parallel()
+
+	directory := filepath.Join(t.TempDir(), "dumps[1]")
+	if os.Mkdir(directory, 0o700) != nil {
+		t.FailNow()
+	}
+
+	path := filepath.Join(directory, "errors.log")
+
+	dump, err := errorsdump.Open(path, errorsdump.Options{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	if dump.Write(ErrPaymentFailed.Raise(1)) != nil || dump.Rotate() != nil || dump.Close() != nil {
+		t.FailNow()
+	}
+
+	unrelated := []string{path + ".bak", path + ".20260101T000000"}
+	for _, name := range unrelated {
+		if os.WriteFile(name, []byte(`{"message":"backup"}`+"\n"), 0o600) != nil {
+			t.FailNow()
+		}
+	}
+
+	shipped := 0
+	err = errorsdump.Replay(path, func(record errorsdump.Record) error {
+		if record.Message != "payment of 1 failed" {
+			t.FailNow()
+		}
+
+		shipped++
+
+		return nil
+	})
+	if err != nil || shipped != 1 {
+		t.FailNow()
+	}
+
+	for _, name := range unrelated {
+		if _, err := os.Stat(name); err != nil {
+			t.FailNow()
+		}
+	}
+}
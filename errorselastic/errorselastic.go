@@ -0,0 +1,250 @@
+// Package errorselastic implements functions to report errors to Elastic APM.
+package errorselastic
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_INTAKE_PATH     = "/intake/v2/events"
+	_DEFAULT_TIMEOUT = 10 * time.Second
+)
+
+// ErrReport is raised when an error cannot be delivered to the APM Server.
+var ErrReport = errors.New("cannot report to Elastic APM")
+
+// StackFrame represents a frame of an Elastic APM exception.
+type StackFrame struct {
+	Filename     string `json:"filename"`
+	AbsPath      string `json:"abs_path,omitempty"`
+	Lineno       int    `json:"lineno"`
+	Function     string `json:"function"`
+	Module       string `json:"module,omitempty"`
+	LibraryFrame bool   `json:"library_frame"`
+}
+
+// Exception represents an Elastic APM exception, with the wrapped errors as causes.
+type Exception struct {
+	Message    string       `json:"message"`
+	Type       string       `json:"type"`
+	Module     string       `json:"module,omitempty"`
+	Code       string       `json:"code,omitempty"`
+	Handled    bool         `json:"handled"`
+	Stacktrace []StackFrame `json:"stacktrace,omitempty"`
+	Cause      []Exception  `json:"cause,omitempty"`
+}
+
+// Context represents the context of an Elastic APM error.
+type Context struct {
+	Custom map[string]any    `json:"custom,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+}
+
+// Error represents an Elastic APM error event following the Intake API v2.
+type Error struct {
+	ID            string    `json:"id"`
+	Timestamp     int64     `json:"timestamp"`
+	TraceID       string    `json:"trace_id,omitempty"`
+	TransactionID string    `json:"transaction_id,omitempty"`
+	ParentID      string    `json:"parent_id,omitempty"`
+	Culprit       string    `json:"culprit,omitempty"`
+	Exception     Exception `json:"exception"`
+	Context       *Context  `json:"context,omitempty"`
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+func newException(err error) Exception {
+	var cerr *errors.Error
+
+	switch typed := err.(type) {
+	case *errors.Error:
+		cerr = typed
+	case errors.Error:
+		cerr = &typed
+	}
+
+	if cerr == nil {
+		return Exception{
+			Message: err.Error(),
+			Type:    strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+			Handled: true,
+		}
+	}
+
+	exception := Exception{
+		Message: cerr.Message(),
+		Type:    cerr.Kind(),
+		Module:  cerr.Module(),
+		Code:    cerr.Code(),
+		Handled: true,
+	}
+
+	for _, frame := range cerr.StackTrace() {
+		exception.Stacktrace = append(exception.Stacktrace, StackFrame{
+			Filename:     frame.File[strings.LastIndex(frame.File, "/")+1:],
+			AbsPath:      frame.File,
+			Lineno:       frame.Line,
+			Function:     frame.Function,
+			LibraryFrame: frame.Class != errors.FrameInApp,
+		})
+	}
+
+	if cause := cerr.Unwrap(); cause != nil {
+		exception.Cause = []Exception{newException(cause)}
+	}
+
+	return exception
+}
+
+// NewError converts an error chain into an Elastic APM error event linked to the
+// transaction of the active span in the context, or of the trace_id and span_id
+// tags of the Error otherwise (see errorsotel), with the culprit from the most
+// recent in-app frame and the extra of the chain as custom context, the outermost
+// errors winning.
+func NewError(ctx context.Context, err error) Error {
+	event := Error{
+		ID:        newID(),
+		Timestamp: time.Now().UnixMicro(),
+		Exception: newException(err),
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		event.TraceID = spanContext.TraceID().String()
+		event.TransactionID = spanContext.SpanID().String()
+		event.ParentID = event.TransactionID
+	}
+
+	var rerr errors.Error
+	if !goerrors.As(err, &rerr) {
+		return event
+	}
+
+	if !rerr.RaisedAt().IsZero() {
+		event.Timestamp = rerr.RaisedAt().UnixMicro()
+	}
+
+	if tags := rerr.GetTags(); event.TraceID == "" && tags["trace_id"] != "" && tags["span_id"] != "" {
+		event.TraceID = tags["trace_id"]
+		event.TransactionID = tags["span_id"]
+		event.ParentID = tags["span_id"]
+	}
+
+	for _, frame := range rerr.StackTrace(errors.OnlyInApp) {
+		event.Culprit = frame.Function
+		break
+	}
+
+	tags := rerr.GetTags()
+	extras := rerr.AllExtras()
+
+	if len(tags) > 0 || len(extras) > 0 {
+		event.Context = &Context{}
+
+		if len(tags) > 0 {
+			event.Context.Tags = tags
+		}
+
+		if len(extras) > 0 {
+			event.Context.Custom = extras
+		}
+	}
+
+	return event
+}
+
+// Reporter reports errors to an Elastic APM Server through its Intake API v2.
+type Reporter struct {
+	ServerURL   string
+	SecretToken string
+	ServiceName string
+	Environment string
+	Client      *http.Client
+	// Timeout is the maximum time to report an error when used as a sink
+	// (default is 10 seconds).
+	Timeout time.Duration
+}
+
+// Report reports an error to the APM Server, linked to the transaction of the
+// active span in the context.
+func (self Reporter) Report(ctx context.Context, err error) error {
+	var payload bytes.Buffer
+
+	encoder := json.NewEncoder(&payload)
+
+	metadata := map[string]any{
+		"service": map[string]any{
+			"name":        self.ServiceName,
+			"environment": self.Environment,
+			"agent":       map[string]string{"name": "neoxelox/errors", "version": "1.0.0"},
+			"language":    map[string]string{"name": "go"},
+		},
+	}
+
+	if eerr := encoder.Encode(map[string]any{"metadata": metadata}); eerr != nil {
+		return ErrReport.Raise().Cause(eerr)
+	}
+
+	if eerr := encoder.Encode(map[string]any{"error": NewError(ctx, err)}); eerr != nil {
+		return ErrReport.Raise().Cause(eerr)
+	}
+
+	request, rerr := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(self.ServerURL, "/")+_INTAKE_PATH, &payload)
+	if rerr != nil {
+		return ErrReport.Raise().Cause(rerr)
+	}
+
+	request.Header.Set("Content-Type", "application/x-ndjson")
+	if self.SecretToken != "" {
+		request.Header.Set("Authorization", "Bearer "+self.SecretToken)
+	}
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, rerr := client.Do(request)
+	if rerr != nil {
+		return ErrReport.Raise().Cause(rerr)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return ErrReport.Raise().With("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Write implements the errors.Sink interface.
+func (self Reporter) Write(err error) error {
+	timeout := self.Timeout
+	if timeout <= 0 {
+		timeout = _DEFAULT_TIMEOUT
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return self.Report(ctx, err)
+}
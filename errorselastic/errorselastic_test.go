@@ -0,0 +1,132 @@
+package errorselastic_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorselastic"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrCannotDeposit = errors.NewWithOptions("cannot deposit", errors.NewOptions{Code: "DEPOSIT"})
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	var lines []map[string]json.RawMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/intake/v2/events" || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			line := map[string]json.RawMessage{}
+			_ = json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"amount": 100}).
+		Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700, "amount": 0}))
+
+	reporter := errorselastic.Reporter{ServerURL: server.URL + "/", SecretToken: "token", ServiceName: "payments"}
+	if reporter.Report(ctx, err) != nil || len(lines) != 2 || lines[0]["metadata"] == nil {
+		t.FailNow()
+	}
+
+	var event errorselastic.Error
+	if json.Unmarshal(lines[1]["error"], &event) != nil {
+		t.FailNow()
+	}
+
+	if event.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || event.TransactionID != "00f067aa0ba902b7" {
+		t.FailNow()
+	}
+
+	if event.Culprit != "github.com/neoxelox/errors/errorselastic_test.TestReport" || len(event.ID) != 32 {
+		t.FailNow()
+	}
+
+	if event.Exception.Code != "DEPOSIT" || len(event.Exception.Cause) != 1 ||
+		event.Exception.Cause[0].Message != "user Alex not found" || len(event.Exception.Stacktrace) == 0 {
+		t.FailNow()
+	}
+
+	if event.Context.Custom["amount"] != 100.0 || event.Context.Custom["userID"] != 310700.0 {
+		t.FailNow()
+	}
+
+	if (errorselastic.Reporter{ServerURL: server.URL}).Write(err) == nil {
+		t.FailNow()
+	}
+}
+
+func TestNewErrorFromTags(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Tags(map[string]any{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	})
+
+	event := errorselastic.NewError(context.Background(), err)
+	if event.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || event.ParentID != "00f067aa0ba902b7" {
+		t.FailNow()
+	}
+}
+
+func TestNewErrorWithoutContext(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(errorselastic.NewError(context.Background(), ErrCannotDeposit.Raise()))
+	if err != nil {
+		t.FailNow()
+	}
+
+	var event map[string]json.RawMessage
+	if json.Unmarshal(data, &event) != nil {
+		t.FailNow()
+	}
+
+	if _, ok := event["context"]; ok {
+		t.FailNow()
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	reporter := errorselastic.Reporter{ServerURL: server.URL, Timeout: 10 * time.Millisecond}
+	if err := reporter.Write(ErrCannotDeposit.Raise()); !errorselastic.ErrReport.Is(err) {
+		t.FailNow()
+	}
+}
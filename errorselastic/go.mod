@@ -0,0 +1,15 @@
+module github.com/neoxelox/errors/errorselastic
+
+go 1.21.1
+
+require (
+	github.com/neoxelox/errors v0.0.0-20261016142157-8062b4b23cef
+	go.opentelemetry.io/otel/trace v1.27.0
+)
+
+require (
+	github.com/getsentry/sentry-go v0.28.0 // indirect
+	go.opentelemetry.io/otel v1.27.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+)
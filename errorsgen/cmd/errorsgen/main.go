@@ -0,0 +1,54 @@
+// Command errorsgen generates Error declarations and their Markdown documentation
+// from a YAML or JSON catalog, to be run with go:generate:
+//
+//	//go:generate go run github.com/neoxelox/errors/errorsgen/cmd/errorsgen -catalog errors.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/neoxelox/errors/errorsgen"
+)
+
+func main() {
+	catalogPath := flag.String("catalog", "errors.yaml", "path of the YAML or JSON catalog")
+	outPath := flag.String("out", "errors_gen.go", "path of the generated Go file")
+	docsPath := flag.String("docs", "", "path of the generated Markdown documentation (default is none)")
+	flag.Parse()
+
+	if err := run(*catalogPath, *outPath, *docsPath); err != nil {
+		fmt.Fprintln(os.Stderr, "errorsgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(catalogPath string, outPath string, docsPath string) error {
+	data, err := os.ReadFile(catalogPath)
+	if err != nil {
+		return err
+	}
+
+	catalog, err := errorsgen.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	source, err := errorsgen.Go(catalog)
+	if err != nil {
+		return err
+	}
+
+	// nolint:gosec
+	if err := os.WriteFile(outPath, source, 0o644); err != nil {
+		return err
+	}
+
+	if docsPath == "" {
+		return nil
+	}
+
+	// nolint:gosec
+	return os.WriteFile(docsPath, errorsgen.Markdown(catalog), 0o644)
+}
@@ -0,0 +1,200 @@
+// Package errorsgen implements the generation of Error declarations and their
+// documentation from a declarative catalog, used by the errorsgen command.
+package errorsgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/neoxelox/errors"
+)
+
+// ErrCatalog is raised when a catalog cannot be parsed or is invalid.
+var ErrCatalog = errors.New("invalid error catalog")
+
+// Definition represents the declaration of an Error in a catalog.
+type Definition struct {
+	// Name is the name of the declared variable, such as ErrPaymentDeclined.
+	Name string `yaml:"name"`
+	// Code is the stable textual identifier of the Error.
+	Code string `yaml:"code"`
+	// Message is the message of the Error (can have a format).
+	Message string `yaml:"message"`
+	// Status is the HTTP status code of the Error, registered in errorshttp.
+	Status int `yaml:"status"`
+	// Severity is the level of the Error: warning, error or fatal (default is error).
+	Severity string `yaml:"severity"`
+	// Docs is the URL of the documentation of the Error.
+	Docs string `yaml:"docs"`
+	// Description explains when the Error is raised.
+	Description string `yaml:"description"`
+}
+
+// Catalog represents a declarative catalog of Errors of a package.
+type Catalog struct {
+	// Package is the name of the package of the generated code.
+	Package string `yaml:"package"`
+	// Errors are the declarations of the Errors of the package.
+	Errors []Definition `yaml:"errors"`
+}
+
+var _levels = map[string]string{
+	"":        "",
+	"warning": "errors.LevelWarning",
+	"error":   "",
+	"fatal":   "errors.LevelFatal",
+}
+
+// Parse parses and validates a catalog in YAML or JSON.
+func Parse(data []byte) (*Catalog, error) {
+	catalog := &Catalog{}
+
+	if err := yaml.Unmarshal(data, catalog); err != nil {
+		return nil, ErrCatalog.Raise().Cause(err)
+	}
+
+	if !token.IsIdentifier(catalog.Package) {
+		return nil, ErrCatalog.Raise().With("invalid package %q", catalog.Package)
+	}
+
+	names := make(map[string]bool, len(catalog.Errors))
+	codes := make(map[string]bool, len(catalog.Errors))
+
+	for _, definition := range catalog.Errors {
+		if !token.IsIdentifier(definition.Name) || !token.IsExported(definition.Name) {
+			return nil, ErrCatalog.Raise().With("invalid name %q", definition.Name)
+		}
+
+		if names[definition.Name] {
+			return nil, ErrCatalog.Raise().With("duplicated name %q", definition.Name)
+		}
+
+		if definition.Code != "" && codes[definition.Code] {
+			return nil, ErrCatalog.Raise().With("duplicated code %q", definition.Code)
+		}
+
+		if definition.Message == "" {
+			return nil, ErrCatalog.Raise().With("missing message of %s", definition.Name)
+		}
+
+		if _, ok := _levels[definition.Severity]; !ok {
+			return nil, ErrCatalog.Raise().With("invalid severity %q of %s", definition.Severity, definition.Name)
+		}
+
+		names[definition.Name] = true
+		codes[definition.Code] = true
+	}
+
+	return catalog, nil
+}
+
+// Go returns the formatted Go source code declaring the Errors of the catalog
+// and registering their HTTP status codes.
+func Go(catalog *Catalog) ([]byte, error) {
+	var source bytes.Buffer
+
+	statuses := false
+	for _, definition := range catalog.Errors {
+		statuses = statuses || definition.Status != 0
+	}
+
+	source.WriteString("// Code generated by errorsgen. DO NOT EDIT.\n\n")
+	source.WriteString("package " + catalog.Package + "\n\n")
+	source.WriteString("import (\n\t\"github.com/neoxelox/errors\"\n")
+	if statuses {
+		source.WriteString("\t\"github.com/neoxelox/errors/errorshttp\"\n")
+	}
+	source.WriteString(")\n\n")
+
+	source.WriteString("var (\n")
+	for _, definition := range catalog.Errors {
+		if definition.Description != "" {
+			source.WriteString("\t// " + definition.Name + " " + strings.TrimSpace(definition.Description) + "\n")
+		}
+
+		if definition.Docs != "" {
+			source.WriteString("\t// See " + definition.Docs + "\n")
+		}
+
+		options := []string{}
+		if definition.Code != "" {
+			options = append(options, "Code: "+strconv.Quote(definition.Code))
+		}
+
+		if level := _levels[definition.Severity]; level != "" {
+			options = append(options, "Level: "+level)
+		}
+
+		if definition.Docs != "" {
+			options = append(options, "Docs: "+strconv.Quote(definition.Docs))
+		}
+
+		source.WriteString(fmt.Sprintf("\t%s = errors.NewWithOptions(%s, errors.NewOptions{%s})\n",
+			definition.Name, strconv.Quote(definition.Message), strings.Join(options, ", ")))
+	}
+	source.WriteString(")\n")
+
+	if statuses {
+		source.WriteString("\nfunc init() {\n")
+		for _, definition := range catalog.Errors {
+			if definition.Status != 0 {
+				source.WriteString(fmt.Sprintf("\terrorshttp.Register(%s, %d)\n", definition.Name, definition.Status))
+			}
+		}
+		source.WriteString("}\n")
+	}
+
+	formatted, err := format.Source(source.Bytes())
+	if err != nil {
+		return nil, ErrCatalog.Raise().Cause(err)
+	}
+
+	return formatted, nil
+}
+
+// Markdown returns the documentation of the Errors of the catalog as a Markdown
+// table sorted by code.
+func Markdown(catalog *Catalog) []byte {
+	definitions := append([]Definition(nil), catalog.Errors...)
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+
+	escape := strings.NewReplacer("|", "\\|", "\n", " ").Replace
+
+	var docs bytes.Buffer
+
+	docs.WriteString("# " + catalog.Package + " errors\n\n")
+	docs.WriteString("<!-- Code generated by errorsgen. DO NOT EDIT. -->\n\n")
+	docs.WriteString("| Code | Name | Message | HTTP status | Severity | Description |\n")
+	docs.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, definition := range definitions {
+		code := "`" + definition.Code + "`"
+		if definition.Docs != "" {
+			code = "[" + code + "](" + definition.Docs + ")"
+		}
+
+		status := ""
+		if definition.Status != 0 {
+			status = strconv.Itoa(definition.Status)
+		}
+
+		severity := definition.Severity
+		if severity == "" {
+			severity = "error"
+		}
+
+		docs.WriteString("| " + code + " | `" + definition.Name + "` | " + escape(definition.Message) + " | " +
+			status + " | " + severity + " | " + escape(definition.Description) + " |\n")
+	}
+
+	return docs.Bytes()
+}
@@ -0,0 +1,60 @@
+package errorsgen_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/neoxelox/errors/errorsgen"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/catalog.yaml")
+	if err != nil {
+		t.FailNow()
+	}
+
+	catalog, err := errorsgen.Parse(data)
+	if err != nil {
+		t.FailNow()
+	}
+
+	source, err := errorsgen.Go(catalog)
+	if err != nil {
+		t.FailNow()
+	}
+
+	expected, err := os.ReadFile("testdata/errors_gen.go.golden")
+	if err != nil || string(source) != string(expected) {
+		t.FailNow()
+	}
+
+	expected, err = os.ReadFile("testdata/errors.md.golden")
+	if err != nil || string(errorsgen.Markdown(catalog)) != string(expected) {
+		t.FailNow()
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	if _, err := errorsgen.Parse([]byte(`{"package": "billing", "errors": [{"name": "ErrX", "message": "x"}]}`)); err != nil {
+		t.FailNow()
+	}
+
+	invalid := []string{
+		`{"package": "billing", "errors": [{"name": "errX", "message": "x"}]}`,
+		`{"package": "billing", "errors": [{"name": "ErrX"}]}`,
+		`{"package": "billing", "errors": [{"name": "ErrX", "message": "x", "severity": "panic"}]}`,
+		`{"package": "billing", "errors": [{"name": "ErrX", "message": "x"}, {"name": "ErrX", "message": "y"}]}`,
+		`{"package": "", "errors": []}`,
+		`[`,
+	}
+
+	for _, catalog := range invalid {
+		if _, err := errorsgen.Parse([]byte(catalog)); !errorsgen.ErrCatalog.Is(err) {
+			t.FailNow()
+		}
+	}
+}
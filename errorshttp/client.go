@@ -0,0 +1,146 @@
+package errorshttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/neoxelox/errors"
+)
+
+type envelope struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	TraceID  string `json:"trace_id"`
+	EventID  string `json:"event_id"`
+	Title    string `json:"title"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+}
+
+// Do sends the request with the client (default is http.DefaultClient) and decodes
+// the error envelope of 4xx and 5xx responses into a raised Error (see
+// DecodeResponse), returned alongside the response, whose body remains readable.
+func Do(client *http.Client, request *http.Request) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return response, err
+	}
+
+	if rerr := DecodeResponse(response); rerr != nil {
+		return response, rerr
+	}
+
+	return response, nil
+}
+
+// DecodeResponse decodes the error envelope of a 4xx or 5xx response, both the API
+// body written by WriteError and problem+json bodies, into a raised Error. The
+// Error is the registered template with the envelope's code, or ErrUpstream
+// otherwise, with the public message of the envelope and its code, status code,
+// instance and trace IDs as extra. Bodies that are not an envelope are raised as
+// an ErrResponse (see FromResponse). It returns nil for other responses. The
+// response body remains readable afterwards.
+func DecodeResponse(response *http.Response) *errors.Error {
+	if response == nil || response.Body == nil || response.StatusCode < 400 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	if mediaType != "application/json" && mediaType != "application/problem+json" {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, _MAX_BODY_SIZE))
+	if err != nil {
+		// Restores the partially read body so the caller can still read and close it.
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+
+		return nil
+	}
+
+	response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	var decoded envelope
+	if mediaType == "application/json" {
+		var apiBody struct {
+			Error *envelope `json:"error"`
+		}
+
+		if json.Unmarshal(body, &apiBody) != nil || apiBody.Error == nil {
+			return FromResponse(response)
+		}
+
+		decoded = *apiBody.Error
+	} else if json.Unmarshal(body, &decoded) != nil {
+		return FromResponse(response)
+	}
+
+	return decodeEnvelope(decoded, response.StatusCode)
+}
+
+func decodeEnvelope(decoded envelope, status int) *errors.Error {
+	message := decoded.Message
+	if message == "" {
+		message = decoded.Detail
+	}
+
+	if message == "" {
+		message = decoded.Title
+	}
+
+	instance := decoded.Instance
+	if instance == "" {
+		instance = decoded.EventID
+	}
+
+	extra := map[string]any{"statusCode": status}
+	if decoded.Code != "" {
+		extra["code"] = decoded.Code
+	}
+
+	if instance != "" {
+		extra["instance"] = instance
+	}
+
+	if decoded.TraceID != "" {
+		extra["trace_id"] = decoded.TraceID
+	}
+
+	template := ErrUpstream
+	if decoded.Code != "" {
+		if registered, ok := lookupCode(decoded.Code); ok {
+			template = registered
+		}
+	}
+
+	if message == "" {
+		return template.Raise().Extra(extra)
+	}
+
+	return template.RaiseMessage(message).Extra(extra)
+}
+
+// lookupCode returns the registered template with the code.
+func lookupCode(code string) (errors.Template, bool) {
+	_statuses.RLock()
+	defer _statuses.RUnlock()
+
+	for _, template := range _statuses.templates {
+		if template.Code() == code {
+			return template, true
+		}
+	}
+
+	return errors.Template{}, false
+}
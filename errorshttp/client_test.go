@@ -0,0 +1,137 @@
+package errorshttp_test
+
+import (
+	"context"
+	goerrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorshttp"
+)
+
+func TestDo(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users":
+			errorshttp.WriteError(w, errors.NotFound.Raise().Tags(map[string]any{"trace_id": "4bf92f35"}))
+		case "/problem":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"title":"Payment required","detail":"card declined","instance":"/payments/1"}`))
+		case "/text":
+			http.Error(w, "boom", http.StatusBadGateway)
+		case "/cached":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotModified)
+		case "/partial":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"error":`))
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+	}))
+	defer server.Close()
+
+	get := func(path string) (*http.Response, error) {
+		request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.FailNow()
+		}
+
+		return errorshttp.Do(nil, request)
+	}
+
+	response, err := get("/users")
+
+	var rerr *errors.Error
+	if !goerrors.As(err, &rerr) || !errors.NotFound.Is(rerr) {
+		t.FailNow()
+	}
+
+	if rerr.Extras()["statusCode"] != http.StatusNotFound || rerr.Extras()["trace_id"] != "4bf92f35" {
+		t.FailNow()
+	}
+
+	// The response is kept with its body readable
+	if response == nil || response.StatusCode != http.StatusNotFound {
+		t.FailNow()
+	}
+
+	if body, _ := io.ReadAll(response.Body); !strings.Contains(string(body), `"code":"NOT_FOUND"`) {
+		t.FailNow()
+	}
+
+	response.Body.Close()
+
+	_, err = get("/problem")
+	if !goerrors.As(err, &rerr) || !errorshttp.ErrUpstream.Is(rerr) || rerr.Message() != "card declined" {
+		t.FailNow()
+	}
+
+	if rerr.Extras()["instance"] != "/payments/1" {
+		t.FailNow()
+	}
+
+	response, err = get("/text")
+	if err != nil || response.StatusCode != http.StatusBadGateway {
+		t.FailNow()
+	}
+
+	response.Body.Close()
+
+	response, err = get("/cached")
+	if err != nil || response.StatusCode != http.StatusNotModified {
+		t.FailNow()
+	}
+
+	response.Body.Close()
+
+	response, err = get("/partial")
+	if err != nil || response.StatusCode != http.StatusInternalServerError {
+		t.FailNow()
+	}
+
+	body, _ := io.ReadAll(response.Body)
+	if string(body) != `{"error":` {
+		t.FailNow()
+	}
+
+	response.Body.Close()
+}
+
+func TestDecodeResponse(t *testing.T) {
+	t.Parallel()
+
+	if errorshttp.DecodeResponse(nil) != nil {
+		t.FailNow()
+	}
+
+	response := &http.Response{
+		StatusCode: http.StatusConflict,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error":{"code":"CONFLICT","message":"conflict"}}`)),
+	}
+
+	rerr := errorshttp.DecodeResponse(response)
+	if rerr == nil || !errors.Conflict.Is(rerr) || rerr.Extras()["statusCode"] != http.StatusConflict {
+		t.FailNow()
+	}
+
+	response.Body = io.NopCloser(strings.NewReader(`not json`))
+	if rerr := errorshttp.DecodeResponse(response); rerr == nil || !errorshttp.ErrResponse.Is(rerr) {
+		t.FailNow()
+	}
+
+	response.StatusCode = http.StatusOK
+	if errorshttp.DecodeResponse(response) != nil {
+		t.FailNow()
+	}
+}
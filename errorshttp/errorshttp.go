@@ -0,0 +1,62 @@
+// Package errorshttp implements functions to deal with errors from HTTP responses.
+package errorshttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/neoxelox/errors"
+)
+
+const _MAX_BODY_SIZE = 4 << 10
+
+// ErrResponse is raised by FromResponse for non-2xx responses.
+var ErrResponse = errors.New("%s %s responded with status %d")
+
+// FromResponse raises an ErrResponse from a non-2xx response, including its status
+// code, method, URL and truncated body as extras, and the members of problem+json
+// bodies as structured extra. It returns nil for 2xx responses. The response body
+// remains readable afterwards.
+func FromResponse(resp *http.Response) *errors.Error {
+	if resp == nil || (resp.StatusCode >= 200 && resp.StatusCode < 300) {
+		return nil
+	}
+
+	method := ""
+	url := ""
+	if resp.Request != nil {
+		method = resp.Request.Method
+		if resp.Request.URL != nil {
+			url = resp.Request.URL.Redacted()
+		}
+	}
+
+	extra := map[string]any{
+		"statusCode": resp.StatusCode,
+		"method":     method,
+		"url":        url,
+	}
+
+	if resp.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, _MAX_BODY_SIZE))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+
+		extra["body"] = string(body)
+
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if mediaType == "application/problem+json" {
+			var problem map[string]any
+			if json.Unmarshal(body, &problem) == nil {
+				extra["problem"] = problem
+			}
+		}
+	}
+
+	return ErrResponse.Raise(method, url, resp.StatusCode).Extra(extra).Skip(1)
+}
@@ -0,0 +1,50 @@
+package errorshttp_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorshttp"
+)
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+
+	request := httptest.NewRequest(http.MethodGet, "https://api.example.com/users/3107", nil)
+
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("Content-Type", "application/problem+json")
+	recorder.WriteHeader(http.StatusNotFound)
+	recorder.WriteString(`{"title":"User not found","detail":"user 3107 not found"}`)
+
+	response := recorder.Result()
+	response.Request = request
+
+	err := errorshttp.FromResponse(response)
+	if !errorshttp.ErrResponse.Is(err) {
+		t.FailNow()
+	}
+
+	if err.Error() != "GET https://api.example.com/users/3107 responded with status 404" {
+		t.FailNow()
+	}
+
+	problem, ok := errors.GetExtraAs[map[string]any](err, "problem")
+	if !ok || problem["title"] != "User not found" {
+		t.FailNow()
+	}
+
+	body, _ := io.ReadAll(response.Body)
+	if !strings.Contains(string(body), "user 3107 not found") {
+		t.FailNow()
+	}
+
+	response = httptest.NewRecorder().Result()
+	if errorshttp.FromResponse(response) != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,87 @@
+package errorshttp
+
+import (
+	goerrors "errors"
+	"net/http"
+	"net/url"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_HEADER_CODE     = "X-Error-Code"
+	_HEADER_KIND     = "X-Error-Kind"
+	_HEADER_MODULE   = "X-Error-Module"
+	_HEADER_INSTANCE = "X-Error-Instance"
+	_HEADER_MESSAGE  = "X-Error-Message"
+)
+
+// ErrUpstream is raised by DecodeHeader when the upstream Error's type is not
+// declared within this service.
+var ErrUpstream = errors.New("upstream error")
+
+// EncodeHeader writes a compact form of the error (code, type, package, instance
+// ID and public message) into X-Error-* headers, so proxies and edge services can
+// surface the upstream error identity without parsing bodies. The instance ID is
+// the ID of the Sentry event the Error was reported as, if any, and the message
+// is the Error's own message, never the ones of the errors wrapped within it.
+// Errors not raised by the errors package are encoded as errors.Internal.
+func EncodeHeader(err error, h http.Header) {
+	if err == nil {
+		return
+	}
+
+	code, kind, module, message := errors.Internal.Code(), errors.Internal.Kind(),
+		errors.Internal.Module(), errors.Internal.Message()
+
+	var cerr errors.Error
+	if goerrors.As(err, &cerr) {
+		code, kind, module, message = cerr.Code(), cerr.Kind(), cerr.Module(), cerr.Message()
+
+		if instance := cerr.SentryEventID(); instance != "" {
+			h.Set(_HEADER_INSTANCE, url.QueryEscape(instance))
+		}
+	}
+
+	if code != "" {
+		h.Set(_HEADER_CODE, url.QueryEscape(code))
+	}
+
+	h.Set(_HEADER_KIND, url.QueryEscape(kind))
+	h.Set(_HEADER_MODULE, url.QueryEscape(module))
+	h.Set(_HEADER_MESSAGE, url.QueryEscape(message))
+}
+
+// DecodeHeader raises the Error encoded into X-Error-* headers by EncodeHeader,
+// with its instance ID as extra, or an ErrUpstream with its code, type and
+// package as extra too if it is not declared within this service. It returns nil
+// if there is no error encoded.
+func DecodeHeader(h http.Header) *errors.Error {
+	message, merr := url.QueryUnescape(h.Get(_HEADER_MESSAGE))
+	if merr != nil || message == "" {
+		return nil
+	}
+
+	code, _ := url.QueryUnescape(h.Get(_HEADER_CODE))
+	kind, _ := url.QueryUnescape(h.Get(_HEADER_KIND))
+	module, _ := url.QueryUnescape(h.Get(_HEADER_MODULE))
+	instance, _ := url.QueryUnescape(h.Get(_HEADER_INSTANCE))
+
+	extra := map[string]any{}
+	if instance != "" {
+		extra["instance"] = instance
+	}
+
+	if template, ok := errors.Lookup(module, kind); ok {
+		return template.RaiseMessage(message).Extra(extra).Skip(1)
+	}
+
+	extra["kind"] = kind
+	extra["module"] = module
+
+	if code != "" {
+		extra["code"] = code
+	}
+
+	return ErrUpstream.Raise().With("%s", message).Extra(extra).Skip(1)
+}
@@ -0,0 +1,83 @@
+package errorshttp_test
+
+import (
+	goerrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorshttp"
+)
+
+var (
+	ErrUserNotFound = errors.New("user %s not found")
+	ErrUserBanned   = errors.NewWithOptions("user %s banned", errors.NewOptions{Code: "USER_BANNED"})
+)
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	errorshttp.EncodeHeader(ErrUserNotFound.Raise("Alex"), header)
+
+	err := errorshttp.DecodeHeader(header)
+	if !ErrUserNotFound.Is(err) || err.Error() != "user Alex not found" {
+		t.FailNow()
+	}
+
+	header.Set("X-Error-Kind", "unknown")
+
+	err = errorshttp.DecodeHeader(header)
+	if !errorshttp.ErrUpstream.Is(err) || err.Error() != "upstream error: user Alex not found" {
+		t.FailNow()
+	}
+
+	if errorshttp.DecodeHeader(http.Header{}) != nil {
+		t.FailNow()
+	}
+}
+
+func TestHeaderPublic(t *testing.T) {
+	t.Parallel()
+
+	header := http.Header{}
+	errorshttp.EncodeHeader(ErrUserBanned.Raise("Alex").Cause(goerrors.New("dial tcp 10.0.0.7:5432: refused")), header)
+
+	if header.Get("X-Error-Message") != "user+Alex+banned" {
+		t.FailNow()
+	}
+
+	header = http.Header{}
+	errorshttp.EncodeHeader(goerrors.New("dial tcp 10.0.0.7:5432: refused"), header)
+
+	err := errorshttp.DecodeHeader(header)
+	if header.Get("X-Error-Code") != "INTERNAL" || !errors.Internal.Is(err) || err.Error() != "internal error" {
+		t.FailNow()
+	}
+
+	header.Set("X-Error-Instance", "9f2c1a")
+
+	instance, ok := errors.GetExtraAs[string](errorshttp.DecodeHeader(header), "instance")
+	if !ok || instance != "9f2c1a" {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestHeaderCodePrefix(t *testing.T) {
+	defer errors.SetCodePrefix(false)
+
+	errors.SetCodePrefix(true)
+
+	header := http.Header{}
+	errorshttp.EncodeHeader(ErrUserBanned.Raise("Alex"), header)
+
+	if header.Get("X-Error-Code") != "USER_BANNED" || header.Get("X-Error-Message") != "user+Alex+banned" {
+		t.FailNow()
+	}
+
+	err := errorshttp.DecodeHeader(header)
+	if !ErrUserBanned.Is(err) || err.Error() != "[USER_BANNED] user Alex banned" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,48 @@
+package errorshttp
+
+import (
+	goerrors "errors"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+)
+
+const _HEADER_SENTRY_EVENT_ID = "X-Sentry-Event-ID"
+
+// WriteError writes the error as a response with the status code registered for
+// its type and the versioned API body. Errors with 5xx status codes are reported
+// to Sentry through the hub (default is the current hub) and the ID of the event
+// is included in the X-Sentry-Event-ID header and the body, so support can look
+// up the exact event a customer saw. Errors not raised by the errors package are
+// wrapped into errors.Internal.
+func WriteError(w http.ResponseWriter, err error, hub ...*sentry.Hub) {
+	if err == nil {
+		return
+	}
+
+	var rerr *errors.Error
+	if !goerrors.As(err, &rerr) {
+		rerr = errors.Internal.Raise().Cause(err).Skip(1)
+	}
+
+	status := StatusCode(rerr)
+	if status >= http.StatusInternalServerError {
+		rerr.CaptureSentry(hub...)
+	}
+
+	if eventID := rerr.SentryEventID(); eventID != "" {
+		w.Header().Set(_HEADER_SENTRY_EVENT_ID, eventID)
+	}
+
+	body, berr := rerr.APIBody(1)
+	if berr != nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
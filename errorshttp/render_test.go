@@ -0,0 +1,50 @@
+package errorshttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorshttp"
+)
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	client, err := sentry.NewClient(sentry.ClientOptions{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	recorder := httptest.NewRecorder()
+	rerr := errors.Internal.Raise()
+	errorshttp.WriteError(recorder, rerr, hub)
+
+	eventID := recorder.Header().Get("X-Sentry-Event-ID")
+	if recorder.Code != http.StatusInternalServerError || eventID == "" || eventID != rerr.SentryEventID() {
+		t.FailNow()
+	}
+
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			EventID string `json:"event_id"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(recorder.Body.Bytes(), &body) != nil || body.Error.EventID != eventID {
+		t.FailNow()
+	}
+
+	recorder = httptest.NewRecorder()
+	errorshttp.WriteError(recorder, errors.NotFound.Raise(), hub)
+
+	if recorder.Code != http.StatusNotFound || recorder.Header().Get("X-Sentry-Event-ID") != "" {
+		t.FailNow()
+	}
+}
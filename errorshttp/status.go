@@ -0,0 +1,68 @@
+package errorshttp
+
+import (
+	goerrors "errors"
+	"net/http"
+	"sync"
+
+	"github.com/neoxelox/errors"
+)
+
+var _statuses = struct {
+	sync.RWMutex
+	templates []errors.Template
+	statuses  []int
+}{
+	templates: []errors.Template{
+		errors.NotFound,
+		errors.Conflict,
+		errors.Unauthorized,
+		errors.InvalidArgument,
+		errors.ResourceExhausted,
+		errors.Internal,
+	},
+	statuses: []int{
+		http.StatusNotFound,
+		http.StatusConflict,
+		http.StatusUnauthorized,
+		http.StatusBadRequest,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+	},
+}
+
+// Register maps the Error's type to an HTTP status code. The canonical Errors
+// are registered by default.
+func Register(template errors.Template, status int) {
+	_statuses.Lock()
+	defer _statuses.Unlock()
+
+	_statuses.templates = append(_statuses.templates, template)
+	_statuses.statuses = append(_statuses.statuses, status)
+}
+
+// StatusCode returns the HTTP status code registered for the first matching type
+// of the error's chain (default is 500).
+func StatusCode(err error) int {
+	var rerr errors.Error
+	if !goerrors.As(err, &rerr) {
+		return http.StatusInternalServerError
+	}
+
+	_statuses.RLock()
+	defer _statuses.RUnlock()
+
+	for i, template := range _statuses.templates {
+		if template.Is(rerr) {
+			return _statuses.statuses[i]
+		}
+	}
+
+	for i, template := range _statuses.templates {
+		if template.In(rerr) {
+			return _statuses.statuses[i]
+		}
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,29 @@
+package errorshttp_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorshttp"
+)
+
+var ErrTeapot = errors.New("i'm a teapot")
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	errorshttp.Register(ErrTeapot, http.StatusTeapot)
+
+	if errorshttp.StatusCode(ErrUserNotFound.Raise("Alex").Cause(errors.NotFound.Raise())) != http.StatusNotFound {
+		t.FailNow()
+	}
+
+	if errorshttp.StatusCode(ErrTeapot.Raise()) != http.StatusTeapot {
+		t.FailNow()
+	}
+
+	if errorshttp.StatusCode(ErrUserNotFound.Raise("Alex")) != http.StatusInternalServerError {
+		t.FailNow()
+	}
+}
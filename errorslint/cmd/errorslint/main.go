@@ -0,0 +1,13 @@
+// Command errorslint runs the analyzers of the errorslint package, standalone or
+// with go vet -vettool.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/neoxelox/errors/errorslint"
+)
+
+func main() {
+	multichecker.Main(errorslint.Analyzers...)
+}
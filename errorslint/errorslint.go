@@ -0,0 +1,374 @@
+// Package errorslint implements static analyzers that catch misuses of Errors
+// the compiler can't see, to be run with go vet or golangci-lint.
+package errorslint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const _ERRORS_PATH = "github.com/neoxelox/errors"
+
+// Analyzers are all the analyzers of this package.
+var Analyzers = []*analysis.Analyzer{RaiseFormat, RaiseTemplate}
+
+// formatFact records the message format of an Error template variable.
+type formatFact struct {
+	Format string
+}
+
+func (*formatFact) AFact() {}
+
+func (self *formatFact) String() string {
+	return "format(" + strconv.Quote(self.Format) + ")"
+}
+
+// RaiseFormat checks that the arguments of Raise, RaiseLazy, RaiseCtx and With
+// calls match the format verbs of the message declared in New.
+var RaiseFormat = &analysis.Analyzer{
+	Name:      "raiseformat",
+	Doc:       "check that the arguments of Raise and With calls match the format of the Error's message",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(formatFact)},
+	Run:       runRaiseFormat,
+}
+
+// isErrorsFunc checks whether the call is to one of the functions of this
+// package.
+func isErrorsFunc(pass *analysis.Pass, call *ast.CallExpr, names ...string) bool {
+	var ident *ast.Ident
+
+	switch fun := astutil.Unparen(call.Fun).(type) {
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.IndexExpr:
+		selector, ok := fun.X.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+
+		ident = selector.Sel
+	default:
+		return false
+	}
+
+	function, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+	if !ok || function.Pkg() == nil || function.Pkg().Path() != _ERRORS_PATH {
+		return false
+	}
+
+	for _, name := range names {
+		if function.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isErrorsMethod returns the names of the receiver type and the method of this
+// package called, if any.
+func isErrorsMethod(pass *analysis.Pass, call *ast.CallExpr) (*ast.SelectorExpr, string, string) {
+	selector, ok := astutil.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil, "", ""
+	}
+
+	selection, ok := pass.TypesInfo.Selections[selector]
+	if !ok || selection.Kind() != types.MethodVal {
+		return nil, "", ""
+	}
+
+	method, ok := selection.Obj().(*types.Func)
+	if !ok || method.Pkg() == nil || method.Pkg().Path() != _ERRORS_PATH {
+		return nil, "", ""
+	}
+
+	receiver := method.Type().(*types.Signature).Recv().Type() // nolint:forcetypeassert
+	if pointer, ok := receiver.(*types.Pointer); ok {
+		receiver = pointer.Elem()
+	}
+
+	named, ok := receiver.(*types.Named)
+	if !ok {
+		return nil, "", ""
+	}
+
+	return selector, named.Obj().Name(), method.Name()
+}
+
+func constantString(pass *analysis.Pass, expr ast.Expr) (string, bool) {
+	value := pass.TypesInfo.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(value), true
+}
+
+// templateVar returns the variable holding an Error template, if any.
+func templateVar(pass *analysis.Pass, expr ast.Expr) *types.Var {
+	var ident *ast.Ident
+
+	switch expr := astutil.Unparen(expr).(type) {
+	case *ast.Ident:
+		ident = expr
+	case *ast.SelectorExpr:
+		ident = expr.Sel
+	default:
+		return nil
+	}
+
+	variable, ok := pass.TypesInfo.Uses[ident].(*types.Var)
+	if !ok || variable.IsField() || variable.Parent() != variable.Pkg().Scope() {
+		return nil
+	}
+
+	return variable
+}
+
+// verbs returns the number of arguments consumed by a format, or false if it
+// can't be determined, such as when explicit argument indexes are used.
+func verbs(format string) (int, bool) {
+	count := 0
+
+	skipDigits := func(i int) int {
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+
+		return i
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+		if i < len(format) && format[i] == '*' {
+			count++
+			i++
+		} else {
+			i = skipDigits(i)
+		}
+
+		if i < len(format) && format[i] == '.' {
+			i++
+			if i < len(format) && format[i] == '*' {
+				count++
+				i++
+			} else {
+				i = skipDigits(i)
+			}
+		}
+
+		if i >= len(format) {
+			break
+		}
+
+		switch format[i] {
+		case '[':
+			return 0, false
+		case '%':
+			continue
+		}
+
+		count++
+	}
+
+	return count, true
+}
+
+func checkArgs(pass *analysis.Pass, call *ast.CallExpr, name string, format string, args []ast.Expr) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+
+	expected, ok := verbs(format)
+	if !ok || expected == len(args) {
+		return
+	}
+
+	pass.Reportf(call.Pos(), "%s call has %d args but format %q needs %d", name, len(args), format, expected)
+}
+
+func runRaiseFormat(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector) // nolint:forcetypeassert
+
+	// Record the formats of the package-level templates declared with New.
+	for _, file := range pass.Files {
+		for _, declaration := range file.Decls {
+			general, ok := declaration.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range general.Specs {
+				value, ok := spec.(*ast.ValueSpec)
+				if !ok || len(value.Names) != len(value.Values) {
+					continue
+				}
+
+				for i, expr := range value.Values {
+					call, ok := astutil.Unparen(expr).(*ast.CallExpr)
+					if !ok || len(call.Args) == 0 ||
+						!isErrorsFunc(pass, call, "New", "NewWarning", "NewWithOptions", "NewOf") {
+						continue
+					}
+
+					format, ok := constantString(pass, call.Args[0])
+					if !ok {
+						continue
+					}
+
+					if variable, ok := pass.TypesInfo.Defs[value.Names[i]].(*types.Var); ok {
+						pass.ExportObjectFact(variable, &formatFact{Format: format})
+					}
+				}
+			}
+		}
+	}
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr) // nolint:forcetypeassert
+
+		selector, receiver, name := isErrorsMethod(pass, call)
+		if selector == nil {
+			return
+		}
+
+		if name == "With" {
+			if len(call.Args) == 0 {
+				return
+			}
+
+			if format, ok := constantString(pass, call.Args[0]); ok {
+				checkArgs(pass, call, name, format, call.Args[1:])
+			}
+
+			return
+		}
+
+		skip := 0
+
+		switch name {
+		case "Raise", "RaiseLazy":
+			// The Raise of templates with typed payloads takes the payload first.
+			if receiver == "Of" {
+				skip = 1
+			}
+		case "RaiseCtx":
+			skip = 1
+		default:
+			return
+		}
+
+		variable := templateVar(pass, selector.X)
+		if variable == nil {
+			return
+		}
+
+		var fact formatFact
+		if !pass.ImportObjectFact(variable, &fact) {
+			return
+		}
+
+		if len(call.Args) < skip {
+			return
+		}
+
+		checkArgs(pass, call, name, fact.Format, call.Args[skip:])
+	})
+
+	return nil, nil // nolint:nilnil
+}
+
+// RaiseTemplate checks that Error templates are raised instead of returned as
+// errors through their Target, which drops their stack trace and hands out the
+// template itself. Templates can't be returned as errors otherwise.
+var RaiseTemplate = &analysis.Analyzer{
+	Name:     "raisetemplate",
+	Doc:      "check that Error templates are raised instead of returned as errors",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runRaiseTemplate,
+}
+
+// isTemplateTarget checks whether the expression is the Target of a
+// package-level variable holding an Error template, such as ErrX.Target().
+func isTemplateTarget(pass *analysis.Pass, expr ast.Expr) (*types.Var, bool) {
+	call, ok := astutil.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil, false
+	}
+
+	selector, receiver, method := isErrorsMethod(pass, call)
+	if selector == nil || receiver != "Template" || method != "Target" {
+		return nil, false
+	}
+
+	variable := templateVar(pass, selector.X)
+	if variable == nil {
+		return nil, false
+	}
+
+	return variable, true
+}
+
+func runRaiseTemplate(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector) // nolint:forcetypeassert
+
+	nodes := []ast.Node{(*ast.ReturnStmt)(nil)}
+	inspect.WithStack(nodes, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		var results *types.Tuple
+
+		// Find the signature of the innermost function returning.
+		for i := len(stack) - 1; i >= 0 && results == nil; i-- {
+			switch function := stack[i].(type) {
+			case *ast.FuncDecl:
+				if object, ok := pass.TypesInfo.Defs[function.Name].(*types.Func); ok {
+					results = object.Type().(*types.Signature).Results() // nolint:forcetypeassert
+				}
+			case *ast.FuncLit:
+				if signature, ok := pass.TypesInfo.TypeOf(function).(*types.Signature); ok {
+					results = signature.Results()
+				}
+			}
+		}
+
+		returned := node.(*ast.ReturnStmt) // nolint:forcetypeassert
+		if results == nil || results.Len() != len(returned.Results) {
+			return true
+		}
+
+		for i, result := range returned.Results {
+			if !types.IsInterface(results.At(i).Type()) {
+				continue
+			}
+
+			if variable, ok := isTemplateTarget(pass, result); ok {
+				pass.Reportf(result.Pos(), "template %s returned as an error without Raise", variable.Name())
+			}
+		}
+
+		return true
+	})
+
+	return nil, nil // nolint:nilnil
+}
@@ -0,0 +1,21 @@
+package errorslint_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/neoxelox/errors/errorslint"
+)
+
+func TestRaiseFormat(t *testing.T) {
+	t.Parallel()
+
+	analysistest.Run(t, analysistest.TestData(), errorslint.RaiseFormat, "raiseformat")
+}
+
+func TestRaiseTemplate(t *testing.T) {
+	t.Parallel()
+
+	analysistest.Run(t, analysistest.TestData(), errorslint.RaiseTemplate, "raisetemplate")
+}
@@ -0,0 +1,39 @@
+package errors
+
+import "context"
+
+type Error struct{ message string }
+
+type Template struct{ template Error }
+
+type Of[T any] struct{ Template }
+
+type NewOptions struct{}
+
+func New(message string, captureStackTrace ...bool) Template {
+	return Template{Error{message: message}}
+}
+
+func NewWithOptions(message string, options NewOptions) Template {
+	return Template{Error{message: message}}
+}
+
+func NewOf[T any](message string, captureStackTrace ...bool) Of[T] { return Of[T]{} }
+
+func (self Error) Error() string { return self.message }
+
+func (self Error) Raise(args ...any) *Error { return &self }
+
+type target struct{ template *Error }
+
+func (self target) Error() string { return self.template.message }
+
+func (self Template) Target() error { return target{template: &self.template} }
+
+func (self Template) Raise(args ...any) *Error { return &self.template }
+
+func (self Template) RaiseCtx(ctx context.Context, args ...any) *Error { return &self.template }
+
+func (self Of[T]) Raise(payload T, args ...any) *Error { return &Error{} }
+
+func (self *Error) With(message string, args ...any) *Error { return self }
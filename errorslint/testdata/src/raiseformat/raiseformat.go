@@ -0,0 +1,29 @@
+package raiseformat
+
+import (
+	"context"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrUserNotFound = errors.New("user %s not found") // want ErrUserNotFound:`format\("user %s not found"\)`
+
+// want +1 ErrPaymentFailed:`format\("payment of %d%% to %s failed"\)`
+var ErrPaymentFailed = errors.NewWithOptions("payment of %d%% to %s failed", errors.NewOptions{})
+
+var ErrInsufficientFunds = errors.NewOf[int]("insufficient funds for %s") // want ErrInsufficientFunds:`format\("insufficient funds for %s"\)`
+
+var ErrIndexed = errors.New("%[1]s and %[1]s") // want ErrIndexed:`format\("%\[1\]s and %\[1\]s"\)`
+
+func raise(ctx context.Context, args []any) {
+	_ = ErrUserNotFound.Raise("Alex")
+	_ = ErrUserNotFound.Raise()                   // want `Raise call has 0 args but format "user %s not found" needs 1`
+	_ = ErrUserNotFound.RaiseCtx(ctx, "Alex", 42) // want `RaiseCtx call has 2 args but format "user %s not found" needs 1`
+	_ = ErrUserNotFound.Raise(args...)
+	_ = ErrPaymentFailed.Raise(42, "Alex")
+	_ = ErrPaymentFailed.Raise(42) // want `Raise call has 1 args but format "payment of %d%% to %s failed" needs 2`
+	_ = ErrInsufficientFunds.Raise(10, "Alex")
+	_ = ErrInsufficientFunds.Raise(10) // want `Raise call has 0 args but format "insufficient funds for %s" needs 1`
+	_ = ErrIndexed.Raise("Alex")
+	_ = ErrUserNotFound.Raise("Alex").With("retrying %d times after %*d", 3) // want `With call has 1 args but format "retrying %d times after %\*d" needs 3`
+}
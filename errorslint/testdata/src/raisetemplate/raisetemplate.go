@@ -0,0 +1,57 @@
+package raisetemplate
+
+import (
+	goerrors "errors"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrUserNotFound = errors.New("user %s not found")
+
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+var ErrAccountLocked = errors.NewOf[string]("account %s locked")
+
+var ErrRaised = *errors.New("account closed").Raise()
+
+func find(name string) error {
+	if name == "" {
+		return ErrUserNotFound.Target() // want `template ErrUserNotFound returned as an error without Raise`
+	}
+
+	return ErrUserNotFound.Raise(name)
+}
+
+func withdraw(amount int) (int, error) {
+	if amount > 10 {
+		return 0, ErrInsufficientFunds.Target() // want `template ErrInsufficientFunds returned as an error without Raise`
+	}
+
+	return amount, nil
+}
+
+func lock(name string) error {
+	if name == "" {
+		return ErrAccountLocked.Target() // want `template ErrAccountLocked returned as an error without Raise`
+	}
+
+	return ErrAccountLocked.Raise(name, name)
+}
+
+func template() errors.Template {
+	return ErrUserNotFound
+}
+
+func closure() func() error {
+	return func() error {
+		return ErrUserNotFound.Target() // want `template ErrUserNotFound returned as an error without Raise`
+	}
+}
+
+func raised() error {
+	return ErrRaised
+}
+
+func matches(err error) bool {
+	return goerrors.Is(err, ErrUserNotFound.Target())
+}
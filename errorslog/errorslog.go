@@ -0,0 +1,78 @@
+// Package errorslog implements a log/slog Handler that expands Errors into structured groups.
+package errorslog
+
+import (
+	"context"
+	goerrors "errors"
+	"log/slog"
+
+	"github.com/neoxelox/errors"
+)
+
+type handler struct {
+	inner slog.Handler
+}
+
+// Handler wraps an inner slog Handler expanding the attributes holding an Error into
+// structured groups (stack trace, extra, tags, causes...), so logs get all the error
+// information without changing every log call.
+func Handler(inner slog.Handler) slog.Handler {
+	return &handler{inner: inner}
+}
+
+func expand(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		attrs := make([]slog.Attr, 0, len(group))
+		for _, attr := range group {
+			attrs = append(attrs, expand(attr))
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		err, ok := value.Any().(error)
+		if !ok {
+			return attr
+		}
+
+		var cerr errors.Error
+		if !goerrors.As(err, &cerr) {
+			return attr
+		}
+
+		return slog.Attr{Key: attr.Key, Value: cerr.SlogValue()}
+	default:
+		return attr
+	}
+}
+
+func (self *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return self.inner.Enabled(ctx, level)
+}
+
+func (self *handler) Handle(ctx context.Context, record slog.Record) error {
+	expanded := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+
+	record.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(expand(attr))
+		return true
+	})
+
+	return self.inner.Handle(ctx, expanded)
+}
+
+func (self *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		expanded = append(expanded, expand(attr))
+	}
+
+	return &handler{inner: self.inner.WithAttrs(expanded)}
+}
+
+func (self *handler) WithGroup(name string) slog.Handler {
+	return &handler{inner: self.inner.WithGroup(name)}
+}
@@ -0,0 +1,43 @@
+package errorslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorslog"
+)
+
+var ErrUserNotFound = errors.New("user %s not found")
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	logger := slog.New(errorslog.Handler(slog.NewJSONHandler(&output, nil)))
+	logger.Error("request failed", "error", ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700}))
+
+	var record struct {
+		Error struct {
+			Kind    string         `json:"kind"`
+			Message string         `json:"message"`
+			Stack   []string       `json:"stack"`
+			Extra   map[string]any `json:"extra"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(output.Bytes(), &record); err != nil {
+		t.FailNow()
+	}
+
+	if record.Error.Kind != "user %s not found" || record.Error.Message != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if len(record.Error.Stack) == 0 || record.Error.Extra["userID"] != 310700.0 {
+		t.FailNow()
+	}
+}
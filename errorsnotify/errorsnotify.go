@@ -0,0 +1,281 @@
+// Package errorsnotify implements a webhook notifier of errors for chats such as
+// Slack, Discord or Microsoft Teams, usable as a sink of errors.Report.
+package errorsnotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_DEFAULT_MAX_FRAMES       = 5
+	_DEFAULT_INTERVAL         = time.Minute
+	_DEFAULT_TIMEOUT          = 10 * time.Second
+	_MAX_DISCORD_CONTENT_SIZE = 2000
+)
+
+// ErrNotify is raised when a notification cannot be delivered to the webhook.
+var ErrNotify = errors.New("cannot notify webhook")
+
+// Format represents the payload format of a webhook.
+type Format int
+
+const (
+	// FormatSlack is the format of Slack incoming webhooks.
+	FormatSlack Format = iota
+	// FormatDiscord is the format of Discord webhooks.
+	FormatDiscord
+	// FormatTeams is the format of Microsoft Teams incoming webhooks (message cards).
+	FormatTeams
+)
+
+// Options represents the options of a Webhook.
+type Options struct {
+	// Format is the payload format of the webhook (default is Slack).
+	Format Format
+	// MinLevel is the minimum level of the notified errors (default is error).
+	MinLevel errors.Level
+	// MaxFrames is the number of most recent frames shown (default is 5).
+	MaxFrames int
+	// Interval is the minimum time between notifications of errors with the same
+	// hash, the suppressed ones being counted in the next notification (default
+	// is one minute).
+	Interval time.Duration
+	// Client is the HTTP client used to post the notifications (default is
+	// http.DefaultClient).
+	Client *http.Client
+	// Timeout is the maximum time to post a notification when used as a sink
+	// (default is 10 seconds).
+	Timeout time.Duration
+}
+
+type occurrence struct {
+	notifiedAt time.Time
+	suppressed int
+}
+
+// Webhook posts the summary line, top frames and extra of errors to a chat.
+type Webhook struct {
+	url         string
+	options     Options
+	mutex       sync.Mutex
+	occurrences map[string]*occurrence
+	sweptAt     time.Time
+}
+
+// New creates a new Webhook posting to the URL with optional options.
+func New(url string, options ...Options) *Webhook {
+	_options := Options{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.MaxFrames <= 0 {
+		_options.MaxFrames = _DEFAULT_MAX_FRAMES
+	}
+
+	if _options.Interval <= 0 {
+		_options.Interval = _DEFAULT_INTERVAL
+	}
+
+	if _options.Client == nil {
+		_options.Client = http.DefaultClient
+	}
+
+	if _options.Timeout <= 0 {
+		_options.Timeout = _DEFAULT_TIMEOUT
+	}
+
+	return &Webhook{
+		url:         url,
+		options:     _options,
+		occurrences: make(map[string]*occurrence),
+	}
+}
+
+// allowed returns whether an error with the hash can be notified now and how
+// many were suppressed since the last notification.
+func (self *Webhook) allowed(hash string) (bool, int) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	now := time.Now()
+
+	self.sweep(now)
+
+	last, ok := self.occurrences[hash]
+	if !ok {
+		self.occurrences[hash] = &occurrence{notifiedAt: now}
+		return true, 0
+	}
+
+	if now.Sub(last.notifiedAt) < self.options.Interval {
+		last.suppressed++
+		return false, 0
+	}
+
+	suppressed := last.suppressed
+	last.notifiedAt = now
+	last.suppressed = 0
+
+	return true, suppressed
+}
+
+// sweep forgets the errors not notified within the last two intervals, at most
+// once per interval, so the occurrences don't grow with every distinct hash. The
+// similar errors suppressed by them are dropped.
+func (self *Webhook) sweep(now time.Time) {
+	if now.Sub(self.sweptAt) < self.options.Interval {
+		return
+	}
+
+	for hash, occurrence := range self.occurrences {
+		if now.Sub(occurrence.notifiedAt) >= 2*self.options.Interval {
+			delete(self.occurrences, hash)
+		}
+	}
+
+	self.sweptAt = now
+}
+
+type notification struct {
+	title  string
+	frames []string
+	extra  [][2]string
+}
+
+func newNotification(err errors.Error, maxFrames int, suppressed int) notification {
+	message := notification{
+		title: "[" + strings.ToUpper(err.Level().String()) + "] " + err.Summary(),
+	}
+
+	if suppressed > 0 {
+		message.title += " (" + strconv.Itoa(suppressed) + " similar suppressed)"
+	}
+
+	for i, frame := range err.StackTrace() {
+		if i >= maxFrames {
+			break
+		}
+
+		message.frames = append(message.frames, frame.Function+" ("+frame.File+":"+strconv.Itoa(frame.Line)+")")
+	}
+
+	extra := err.AllExtras()
+
+	for key, value := range extra {
+		message.extra = append(message.extra, [2]string{key, fmt.Sprintf("%v", value)})
+	}
+
+	sort.Slice(message.extra, func(i, j int) bool {
+		return message.extra[i][0] < message.extra[j][0]
+	})
+
+	return message
+}
+
+func (self notification) markdown(bold string) string {
+	text := bold + self.title + bold
+
+	if len(self.frames) > 0 {
+		text += "\n```\n" + strings.Join(self.frames, "\n") + "\n```"
+	}
+
+	for _, extra := range self.extra {
+		text += "\n`" + extra[0] + "`: " + extra[1]
+	}
+
+	return text
+}
+
+func (self notification) payload(format Format) any {
+	switch format {
+	case FormatDiscord:
+		content := []rune(self.markdown("**"))
+		if len(content) > _MAX_DISCORD_CONTENT_SIZE {
+			content = append(content[:_MAX_DISCORD_CONTENT_SIZE-1], '…')
+		}
+
+		return map[string]any{"content": string(content)}
+	case FormatTeams:
+		facts := make([]map[string]string, 0, len(self.extra))
+		for _, extra := range self.extra {
+			facts = append(facts, map[string]string{"name": extra[0], "value": extra[1]})
+		}
+
+		text := ""
+		if len(self.frames) > 0 {
+			text = "<pre>" + strings.Join(self.frames, "\n") + "</pre>"
+		}
+
+		return map[string]any{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    self.title,
+			"title":      self.title,
+			"themeColor": "D70000",
+			"text":       text,
+			"sections":   []map[string]any{{"facts": facts}},
+		}
+	default:
+		return map[string]any{"text": self.markdown("*")}
+	}
+}
+
+// Notify posts the error to the webhook if its level reaches the minimum level
+// and no error with the same hash was notified within the interval. Foreign
+// errors are not notified.
+func (self *Webhook) Notify(ctx context.Context, err error) error {
+	var cerr errors.Error
+	if !goerrors.As(err, &cerr) || cerr.Level() < self.options.MinLevel {
+		return nil
+	}
+
+	allowed, suppressed := self.allowed(cerr.Hash())
+	if !allowed {
+		return nil
+	}
+
+	payload, perr := json.Marshal(newNotification(cerr, self.options.MaxFrames, suppressed).payload(self.options.Format))
+	if perr != nil {
+		return ErrNotify.Raise().Cause(perr)
+	}
+
+	request, rerr := http.NewRequestWithContext(ctx, http.MethodPost, self.url, bytes.NewReader(payload))
+	if rerr != nil {
+		return ErrNotify.Raise().Cause(rerr)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, derr := self.options.Client.Do(request)
+	if derr != nil {
+		return ErrNotify.Raise().Cause(derr)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return ErrNotify.Raise().With("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Write implements the errors.Sink interface.
+func (self *Webhook) Write(err error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), self.options.Timeout)
+	defer cancel()
+
+	return self.Notify(ctx, err)
+}
@@ -0,0 +1,155 @@
+package errorsnotify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsnotify"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrCannotDeposit = errors.New("cannot deposit")
+	ErrSlowQuery     = errors.NewWarning("slow query")
+)
+
+func newServer() (*httptest.Server, func() []map[string]any) {
+	var mutex sync.Mutex
+	var payloads []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		payloads = append(payloads, payload)
+	}))
+
+	return server, func() []map[string]any {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return append([]map[string]any(nil), payloads...)
+	}
+}
+
+func TestSlack(t *testing.T) {
+	t.Parallel()
+
+	server, payloads := newServer()
+	defer server.Close()
+
+	webhook := errorsnotify.New(server.URL, errorsnotify.Options{Interval: time.Hour})
+
+	err := ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700}))
+	for i := 0; i < 3; i++ {
+		if webhook.Notify(context.Background(), err) != nil {
+			t.FailNow()
+		}
+	}
+
+	if webhook.Write(ErrSlowQuery.Raise()) != nil || len(payloads()) != 1 {
+		t.FailNow()
+	}
+
+	text, _ := payloads()[0]["text"].(string)
+	if !strings.HasPrefix(text, "*[ERROR] cannot deposit → user Alex not found*") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(text, "TestSlack") || !strings.Contains(text, "`userID`: 310700") {
+		t.FailNow()
+	}
+}
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+
+	server, payloads := newServer()
+	defer server.Close()
+
+	webhook := errorsnotify.New(server.URL, errorsnotify.Options{Interval: 10 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if webhook.Notify(context.Background(), ErrCannotDeposit.Raise()) != nil {
+			t.FailNow()
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	// Notifying another error forgets the expired one along with its suppressed count
+	if webhook.Notify(context.Background(), ErrUserNotFound.Raise("Alex")) != nil ||
+		webhook.Notify(context.Background(), ErrCannotDeposit.Raise()) != nil || len(payloads()) != 3 {
+		t.FailNow()
+	}
+
+	if text, _ := payloads()[2]["text"].(string); strings.Contains(text, "suppressed") {
+		t.FailNow()
+	}
+}
+
+func TestFormats(t *testing.T) {
+	t.Parallel()
+
+	server, payloads := newServer()
+	defer server.Close()
+
+	discord := errorsnotify.New(server.URL, errorsnotify.Options{Format: errorsnotify.FormatDiscord})
+	teams := errorsnotify.New(server.URL, errorsnotify.Options{Format: errorsnotify.FormatTeams, MinLevel: errors.LevelWarning})
+
+	if discord.Notify(context.Background(), ErrCannotDeposit.Raise()) != nil {
+		t.FailNow()
+	}
+
+	if teams.Notify(context.Background(), ErrSlowQuery.Raise()) != nil || len(payloads()) != 2 {
+		t.FailNow()
+	}
+
+	if content, _ := payloads()[0]["content"].(string); !strings.HasPrefix(content, "**[ERROR] cannot deposit**") {
+		t.FailNow()
+	}
+
+	if payloads()[1]["@type"] != "MessageCard" || payloads()[1]["title"] != "[WARNING] slow query" {
+		t.FailNow()
+	}
+}
+
+func TestNotifyStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := errorsnotify.New(server.URL).Notify(context.Background(), ErrCannotDeposit.Raise()); !errorsnotify.ErrNotify.Is(err) {
+		t.FailNow()
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	webhook := errorsnotify.New(server.URL, errorsnotify.Options{Timeout: 10 * time.Millisecond})
+	if err := webhook.Write(ErrCannotDeposit.Raise()); !errorsnotify.ErrNotify.Is(err) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,35 @@
+// Package errorsotel implements functions to correlate errors with OpenTelemetry traces.
+package errorsotel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neoxelox/errors"
+)
+
+// Tags returns the trace_id and span_id tags of the active span in the context,
+// if any.
+func Tags(ctx context.Context) map[string]any {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return map[string]any{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
+
+// Setup tags the Errors raised with RaiseCtx with the active span's trace_id and
+// span_id, and optionally sets the format of the trace link rendered in string
+// reports (see errors.SetTraceLink).
+func Setup(traceLink ...string) {
+	errors.AddContextTagger(Tags)
+
+	if len(traceLink) > 0 {
+		errors.SetTraceLink(traceLink[0])
+	}
+}
@@ -0,0 +1,37 @@
+package errorsotel_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsotel"
+)
+
+var ErrUserNotFound = errors.New("user %s not found")
+
+func TestSetup(t *testing.T) {
+	t.Parallel()
+
+	errorsotel.Setup("https://traces.example.com/%s")
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  spanID,
+	}))
+
+	err := ErrUserNotFound.RaiseCtx(ctx, "Alex")
+
+	if err.GetTags()["trace_id"] != traceID.String() || err.GetTags()["span_id"] != spanID.String() {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.StringReport(), "Trace: https://traces.example.com/"+traceID.String()) {
+		t.FailNow()
+	}
+}
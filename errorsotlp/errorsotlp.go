@@ -0,0 +1,439 @@
+// Package errorsotlp implements an exporter that batches the raised errors and
+// ships them as OpenTelemetry logs or span events over OTLP/gRPC, for edge
+// deployments without Sentry nor a logging agent:
+//
+//	conn, err := grpc.NewClient("collector:4317",
+//		grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	...
+//	exporter := errorsotlp.New(conn, errorsotlp.Options{ServiceName: "billing"})
+//	exporter.Start()
+//	defer exporter.Shutdown(context.Background())
+package errorsotlp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_SCOPE_NAME         = "github.com/neoxelox/errors/errorsotlp"
+	_DEFAULT_BATCH_SIZE = 100
+	_DEFAULT_INTERVAL   = 5 * time.Second
+	_DEFAULT_TIMEOUT    = 10 * time.Second
+)
+
+// ErrExport is raised when a batch of errors cannot be exported to the collector.
+var ErrExport = errors.New("cannot export to the OTLP collector")
+
+// Signal represents the OpenTelemetry signal the errors are exported as.
+type Signal int
+
+const (
+	// SignalLogs exports each error as a log record correlated to the span of its
+	// trace_id and span_id tags.
+	SignalLogs Signal = iota
+	// SignalSpans exports each error as the exception event of a zero-duration
+	// span parented to the span of its trace_id and span_id tags, as OTLP cannot
+	// add events to spans already exported.
+	SignalSpans
+)
+
+// Options represents the options of an Exporter.
+type Options struct {
+	// Signal is the signal the errors are exported as (default is logs).
+	Signal Signal
+	// ServiceName is the service.name resource attribute (default is the name of
+	// the executable).
+	ServiceName string
+	// Attributes are additional resource attributes, such as deployment.environment.
+	Attributes map[string]any
+	// Headers are sent as gRPC metadata on every export, such as authorization.
+	Headers map[string]string
+	// MinLevel is the minimum level of the raised errors exported (default is error).
+	MinLevel errors.Level
+	// BatchSize is the number of errors that triggers an export (default is 100).
+	BatchSize int
+	// Interval is the time between exports of incomplete batches (default is 5
+	// seconds).
+	Interval time.Duration
+	// Timeout is the timeout of each export (default is 10 seconds).
+	Timeout time.Duration
+}
+
+// Exporter batches errors and exports them to an OTLP collector.
+type Exporter struct {
+	options     Options
+	logs        collogspb.LogsServiceClient
+	traces      coltracepb.TraceServiceClient
+	resource    *resourcepb.Resource
+	mutex       sync.Mutex
+	batch       []error
+	channel     chan *errors.Error
+	unsubscribe func()
+	done        chan struct{}
+	stopped     chan struct{}
+}
+
+// New creates a new Exporter over the gRPC connection with optional options.
+func New(conn grpc.ClientConnInterface, options ...Options) *Exporter {
+	_options := Options{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.ServiceName == "" {
+		executable, _ := os.Executable()
+		_options.ServiceName = filepath.Base(executable)
+	}
+
+	if _options.BatchSize <= 0 {
+		_options.BatchSize = _DEFAULT_BATCH_SIZE
+	}
+
+	if _options.Interval <= 0 {
+		_options.Interval = _DEFAULT_INTERVAL
+	}
+
+	if _options.Timeout <= 0 {
+		_options.Timeout = _DEFAULT_TIMEOUT
+	}
+
+	resource := &resourcepb.Resource{
+		Attributes: attributes(map[string]any{"service.name": _options.ServiceName}),
+	}
+
+	for _, attribute := range attributes(_options.Attributes) {
+		if attribute.Key != "service.name" {
+			resource.Attributes = append(resource.Attributes, attribute)
+		}
+	}
+
+	return &Exporter{
+		options:  _options,
+		logs:     collogspb.NewLogsServiceClient(conn),
+		traces:   coltracepb.NewTraceServiceClient(conn),
+		resource: resource,
+	}
+}
+
+// Start subscribes the Exporter to the raised errors of the minimum level and
+// exports them in the background, once the batch is full or every interval,
+// until shut down. Export failures in the background drop the batch.
+func (self *Exporter) Start() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.unsubscribe != nil {
+		return
+	}
+
+	self.channel = make(chan *errors.Error, self.options.BatchSize)
+	self.done = make(chan struct{})
+	self.stopped = make(chan struct{})
+
+	// The export errors are not exported themselves to avoid feedback loops
+	self.unsubscribe = errors.Subscribe(self.channel, func(err *errors.Error) bool {
+		return err.Level() >= self.options.MinLevel && !ErrExport.Is(err)
+	})
+
+	go self.run()
+}
+
+func (self *Exporter) run() {
+	defer close(self.stopped)
+
+	ticker := time.NewTicker(self.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-self.done:
+			return
+		case err := <-self.channel:
+			_ = self.Write(err)
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), self.options.Timeout)
+			_ = self.Flush(ctx)
+			cancel()
+		}
+	}
+}
+
+// Write adds an error to the batch, exporting it if full, implementing the
+// errors.Sink interface.
+func (self *Exporter) Write(err error) error {
+	self.mutex.Lock()
+	self.batch = append(self.batch, err)
+	full := len(self.batch) >= self.options.BatchSize
+	self.mutex.Unlock()
+
+	if !full {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), self.options.Timeout)
+	defer cancel()
+
+	return self.Flush(ctx)
+}
+
+// Flush exports the batched errors.
+func (self *Exporter) Flush(ctx context.Context) error {
+	self.mutex.Lock()
+	batch := self.batch
+	self.batch = nil
+	self.mutex.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if len(self.options.Headers) > 0 {
+		pairs := make([]string, 0, 2*len(self.options.Headers))
+		for key, value := range self.options.Headers {
+			pairs = append(pairs, key, value)
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+	}
+
+	var err error
+
+	switch self.options.Signal {
+	case SignalSpans:
+		_, err = self.traces.Export(ctx, self.tracesRequest(batch))
+	default:
+		_, err = self.logs.Export(ctx, self.logsRequest(batch))
+	}
+
+	if err != nil {
+		return ErrExport.Raise().Cause(err).Extra(map[string]any{"errors": len(batch)})
+	}
+
+	return nil
+}
+
+// Shutdown unsubscribes the Exporter from the raised errors and exports the
+// remaining ones.
+func (self *Exporter) Shutdown(ctx context.Context) error {
+	self.mutex.Lock()
+	unsubscribe := self.unsubscribe
+	self.unsubscribe = nil
+	self.mutex.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+		close(self.done)
+		<-self.stopped
+
+		for len(self.channel) > 0 {
+			self.mutex.Lock()
+			self.batch = append(self.batch, <-self.channel)
+			self.mutex.Unlock()
+		}
+	}
+
+	return self.Flush(ctx)
+}
+
+func (self *Exporter) logsRequest(batch []error) *collogspb.ExportLogsServiceRequest {
+	records := make([]*logspb.LogRecord, 0, len(batch))
+
+	for _, err := range batch {
+		entry := newOccurrence(err)
+
+		records = append(records, &logspb.LogRecord{
+			TimeUnixNano:         uint64(entry.time.UnixNano()),
+			ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+			SeverityNumber:       entry.severity,
+			SeverityText:         entry.level.String(),
+			Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: err.Error()}},
+			Attributes:           entry.attributes,
+			TraceId:              entry.traceID,
+			SpanId:               entry.spanID,
+		})
+	}
+
+	return &collogspb.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: self.resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      &commonpb.InstrumentationScope{Name: _SCOPE_NAME},
+				LogRecords: records,
+			}},
+		}},
+	}
+}
+
+func (self *Exporter) tracesRequest(batch []error) *coltracepb.ExportTraceServiceRequest {
+	spans := make([]*tracepb.Span, 0, len(batch))
+
+	for _, err := range batch {
+		entry := newOccurrence(err)
+
+		span := &tracepb.Span{
+			TraceId:           entry.traceID,
+			SpanId:            newID(8),
+			ParentSpanId:      entry.spanID,
+			Name:              entry.kind,
+			Kind:              tracepb.Span_SPAN_KIND_INTERNAL,
+			StartTimeUnixNano: uint64(entry.time.UnixNano()),
+			EndTimeUnixNano:   uint64(entry.time.UnixNano()),
+			Events: []*tracepb.Span_Event{{
+				TimeUnixNano: uint64(entry.time.UnixNano()),
+				Name:         "exception",
+				Attributes:   entry.attributes,
+			}},
+			Status: &tracepb.Status{Code: tracepb.Status_STATUS_CODE_ERROR, Message: err.Error()},
+		}
+
+		if span.TraceId == nil {
+			span.TraceId = newID(16)
+		}
+
+		spans = append(spans, span)
+	}
+
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: self.resource,
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Scope: &commonpb.InstrumentationScope{Name: _SCOPE_NAME},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+type occurrence struct {
+	kind       string
+	level      errors.Level
+	severity   logspb.SeverityNumber
+	time       time.Time
+	traceID    []byte
+	spanID     []byte
+	attributes []*commonpb.KeyValue
+}
+
+// newOccurrence extracts the OpenTelemetry exception semantic conventions of the
+// error, along with the fields of the Error (see errors.Error.EventFields).
+func newOccurrence(err error) occurrence {
+	result := occurrence{
+		kind:  strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+		level: errors.LevelError,
+		time:  time.Now(),
+	}
+
+	fields := map[string]any{
+		"exception.type":    result.kind,
+		"exception.message": err.Error(),
+	}
+
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		result.kind = rerr.Kind()
+		result.level = rerr.Level()
+
+		if !rerr.RaisedAt().IsZero() {
+			result.time = rerr.RaisedAt()
+		}
+
+		tags := rerr.AllTags()
+		result.traceID, _ = hex.DecodeString(tags["trace_id"])
+		result.spanID, _ = hex.DecodeString(tags["span_id"])
+
+		if len(result.traceID) != 16 || len(result.spanID) != 8 {
+			result.traceID, result.spanID = nil, nil
+		}
+
+		fields = rerr.EventFields()
+		fields["exception.type"] = result.kind
+		fields["exception.message"] = rerr.Message()
+
+		if stack, ok := fields["error.stack"]; ok {
+			fields["exception.stacktrace"] = stack
+			delete(fields, "error.stack")
+		}
+	}
+
+	switch result.level {
+	case errors.LevelWarning:
+		result.severity = logspb.SeverityNumber_SEVERITY_NUMBER_WARN
+	case errors.LevelFatal:
+		result.severity = logspb.SeverityNumber_SEVERITY_NUMBER_FATAL
+	default:
+		result.severity = logspb.SeverityNumber_SEVERITY_NUMBER_ERROR
+	}
+
+	result.attributes = attributes(fields)
+
+	return result
+}
+
+// attributes converts the fields into OTLP attributes sorted by key.
+func attributes(fields map[string]any) []*commonpb.KeyValue {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	attributes := make([]*commonpb.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, &commonpb.KeyValue{Key: key, Value: anyValue(fields[key])})
+	}
+
+	return attributes
+}
+
+func anyValue(value any) *commonpb.AnyValue {
+	switch typed := value.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: typed}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: typed}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(typed)}}
+	case int32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(typed)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: typed}}
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(typed)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: typed}}
+	case fmt.Stringer:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: typed.String()}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprintf("%v", value)}}
+	}
+}
+
+func newID(size int) []byte {
+	id := make([]byte, size)
+	_, _ = rand.Read(id)
+
+	return id
+}
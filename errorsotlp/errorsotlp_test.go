@@ -0,0 +1,209 @@
+package errorsotlp_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsotlp"
+)
+
+var (
+	ErrInvoiceNotFound = errors.New("invoice %s not found")
+	ErrInvoiceStale    = errors.NewWarning("invoice %s is stale")
+)
+
+type collector struct {
+	collogspb.UnimplementedLogsServiceServer
+	coltracepb.UnimplementedTraceServiceServer
+	logs   chan *collogspb.ExportLogsServiceRequest
+	traces chan *coltracepb.ExportTraceServiceRequest
+	tokens chan string
+}
+
+func (self *collector) Export(
+	ctx context.Context, request *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		self.tokens <- md.Get("authorization")[0]
+	}
+
+	self.logs <- request
+
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+type traceCollector struct {
+	*collector
+}
+
+func (self traceCollector) Export(
+	_ context.Context, request *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	self.traces <- request
+
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func newCollector(t *testing.T) (*collector, *grpc.ClientConn) {
+	listener := bufconn.Listen(1 << 20)
+
+	_collector := &collector{
+		logs:   make(chan *collogspb.ExportLogsServiceRequest, 16),
+		traces: make(chan *coltracepb.ExportTraceServiceRequest, 16),
+		tokens: make(chan string, 16),
+	}
+
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, _collector)
+	coltracepb.RegisterTraceServiceServer(server, traceCollector{_collector})
+
+	go func() { _ = server.Serve(listener) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.FailNow()
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+
+	return _collector, conn
+}
+
+func attribute(attributes []*commonpb.KeyValue, key string) string {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return attribute.Value.GetStringValue()
+		}
+	}
+
+	return ""
+}
+
+// nolint:paralleltest
+func TestExporterStart(t *testing.T) {
+	_collector, conn := newCollector(t)
+
+	exporter := errorsotlp.New(conn, errorsotlp.Options{
+		ServiceName: "billing",
+		Headers:     map[string]string{"authorization": "Bearer token"},
+		BatchSize:   1,
+	})
+	exporter.Start()
+
+	_ = ErrInvoiceStale.Raise("in_123")
+	_ = ErrInvoiceNotFound.Raise("in_456").Tags(map[string]any{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	})
+
+	var request *collogspb.ExportLogsServiceRequest
+
+	select {
+	case request = <-_collector.logs:
+	case <-time.After(5 * time.Second):
+		t.FailNow()
+	}
+
+	if err := exporter.Shutdown(context.Background()); err != nil {
+		t.FailNow()
+	}
+
+	if <-_collector.tokens != "Bearer token" {
+		t.FailNow()
+	}
+
+	resource := request.ResourceLogs[0].Resource
+	if attribute(resource.Attributes, "service.name") != "billing" {
+		t.FailNow()
+	}
+
+	records := request.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.FailNow()
+	}
+
+	if records[0].SeverityNumber != logspb.SeverityNumber_SEVERITY_NUMBER_ERROR ||
+		records[0].Body.GetStringValue() != "invoice in_456 not found" ||
+		attribute(records[0].Attributes, "exception.type") != "invoice %s not found" ||
+		attribute(records[0].Attributes, "exception.message") != "invoice in_456 not found" ||
+		attribute(records[0].Attributes, "exception.stacktrace") == "" {
+		t.FailNow()
+	}
+
+	// The tags are added after raising, so the subscribed snapshot lacks them
+	if records[0].TraceId != nil {
+		t.FailNow()
+	}
+}
+
+func TestExporterWrite(t *testing.T) {
+	t.Parallel()
+
+	_collector, conn := newCollector(t)
+
+	exporter := errorsotlp.New(conn, errorsotlp.Options{Signal: errorsotlp.SignalSpans, BatchSize: 2})
+
+	err := ErrInvoiceNotFound.Raise("in_789").Tags(map[string]any{
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":  "00f067aa0ba902b7",
+	})
+
+	if exporter.Write(err) != nil || len(_collector.traces) != 0 {
+		t.FailNow()
+	}
+
+	if exporter.Flush(context.Background()) != nil {
+		t.FailNow()
+	}
+
+	spans := (<-_collector.traces).ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.FailNow()
+	}
+
+	if hex.EncodeToString(spans[0].TraceId) != "4bf92f3577b34da6a3ce929d0e0e4736" ||
+		hex.EncodeToString(spans[0].ParentSpanId) != "00f067aa0ba902b7" ||
+		spans[0].Events[0].Name != "exception" ||
+		attribute(spans[0].Events[0].Attributes, "exception.message") != "invoice in_789 not found" {
+		t.FailNow()
+	}
+}
+
+func TestExporterFlush(t *testing.T) {
+	t.Parallel()
+
+	_, conn := newCollector(t)
+
+	exporter := errorsotlp.New(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = exporter.Write(ErrInvoiceNotFound.Raise("in_000"))
+
+	if err := exporter.Flush(ctx); !errorsotlp.ErrExport.Is(err) {
+		t.FailNow()
+	}
+
+	if exporter.Flush(ctx) != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,20 @@
+module github.com/neoxelox/errors/errorsotlp
+
+go 1.21.1
+
+require (
+	github.com/neoxelox/errors v0.0.0-20261016142157-8062b4b23cef
+	go.opentelemetry.io/proto/otlp v1.3.1
+	google.golang.org/grpc v1.64.0
+)
+
+require (
+	github.com/getsentry/sentry-go v0.28.0 // indirect
+	github.com/grpc-ecosystem/grpc-gateway/v2 v2.20.0 // indirect
+	golang.org/x/net v0.23.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.16.0 // indirect
+	google.golang.org/genproto/googleapis/api v0.0.0-20240513163218-0867130af1f8 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240513163218-0867130af1f8 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
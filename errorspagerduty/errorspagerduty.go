@@ -0,0 +1,170 @@
+// Package errorspagerduty implements functions to trigger PagerDuty incidents
+// from errors through the Events API v2.
+package errorspagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+const (
+	_DEFAULT_ENDPOINT   = "https://events.pagerduty.com/v2/enqueue"
+	_DEFAULT_TIMEOUT    = 10 * time.Second
+	_MAX_SUMMARY_LENGTH = 1024
+)
+
+// ErrNotify is raised when an event cannot be delivered to PagerDuty.
+var ErrNotify = errors.New("cannot notify PagerDuty")
+
+// Payload represents the payload of a PagerDuty event.
+type Payload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// Event represents a PagerDuty event following the Events API v2.
+type Event struct {
+	RoutingKey  string  `json:"routing_key"`
+	EventAction string  `json:"event_action"`
+	DedupKey    string  `json:"dedup_key,omitempty"`
+	Payload     Payload `json:"payload"`
+}
+
+var _severities = map[errors.Level]string{
+	errors.LevelWarning: "warning",
+	errors.LevelError:   "error",
+	errors.LevelFatal:   "critical",
+}
+
+// NewEvent converts an error chain into a PagerDuty trigger event, deduplicated
+// by the stable hash, with the severity mapped from the level and the extra and
+// tags as custom details, the outermost errors winning.
+func NewEvent(err error) Event {
+	event := Event{
+		EventAction: "trigger",
+		Payload: Payload{
+			Summary:   err.Error(),
+			Severity:  "error",
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Class:     strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+		},
+	}
+
+	var rerr errors.Error
+	if !goerrors.As(err, &rerr) {
+		return event
+	}
+
+	event.DedupKey = rerr.Hash()
+	event.Payload.Summary = rerr.Summary()
+	event.Payload.Severity = _severities[rerr.Level()]
+	event.Payload.Component = rerr.Module()
+	event.Payload.Class = rerr.Kind()
+
+	details := make(map[string]any)
+	for key, value := range rerr.AllExtras() {
+		details[key] = value
+	}
+
+	for key, value := range rerr.AllTags() {
+		if _, ok := details[key]; !ok {
+			details[key] = value
+		}
+	}
+
+	if len(details) > 0 {
+		event.Payload.CustomDetails = details
+	}
+
+	if runes := []rune(event.Payload.Summary); len(runes) > _MAX_SUMMARY_LENGTH {
+		event.Payload.Summary = string(runes[:_MAX_SUMMARY_LENGTH-1]) + "…"
+	}
+
+	return event
+}
+
+// Notifier triggers PagerDuty incidents through the Events API v2. Errors with
+// the same hash are deduplicated into the same incident.
+type Notifier struct {
+	RoutingKey string
+	// Source is the affected system (default is the hostname).
+	Source   string
+	Endpoint string
+	Client   *http.Client
+	// Timeout is the maximum time to trigger an incident when used as a sink
+	// (default is 10 seconds).
+	Timeout time.Duration
+}
+
+// Notify triggers a PagerDuty incident for the error.
+func (self Notifier) Notify(ctx context.Context, err error) error {
+	event := NewEvent(err)
+	event.RoutingKey = self.RoutingKey
+
+	event.Payload.Source = self.Source
+	if event.Payload.Source == "" {
+		event.Payload.Source, _ = os.Hostname()
+	}
+
+	payload, perr := json.Marshal(event)
+	if perr != nil {
+		return ErrNotify.Raise().Cause(perr)
+	}
+
+	endpoint := self.Endpoint
+	if endpoint == "" {
+		endpoint = _DEFAULT_ENDPOINT
+	}
+
+	request, rerr := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if rerr != nil {
+		return ErrNotify.Raise().Cause(rerr)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	client := self.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	response, rerr := client.Do(request)
+	if rerr != nil {
+		return ErrNotify.Raise().Cause(rerr)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return ErrNotify.Raise().With("unexpected status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Write implements the errors.Sink interface, so fatal errors can page with
+// errors.AddSink(notifier, errors.LevelFatal).
+func (self Notifier) Write(err error) error {
+	timeout := self.Timeout
+	if timeout <= 0 {
+		timeout = _DEFAULT_TIMEOUT
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return self.Notify(ctx, err)
+}
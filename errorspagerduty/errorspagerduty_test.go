@@ -0,0 +1,86 @@
+package errorspagerduty_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorspagerduty"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user %s not found")
+	ErrDatabaseDown  = errors.NewWithOptions("database down", errors.NewOptions{Level: errors.LevelFatal})
+	ErrCannotDeposit = errors.New("cannot deposit")
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	var event errorspagerduty.Event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	err := ErrDatabaseDown.Raise().Tags(map[string]any{"region": "eu"}).
+		Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 310700}))
+
+	notifier := errorspagerduty.Notifier{RoutingKey: "key", Source: "api", Endpoint: server.URL}
+	if notifier.Write(err) != nil {
+		t.FailNow()
+	}
+
+	if event.RoutingKey != "key" || event.EventAction != "trigger" || event.DedupKey != err.Hash() {
+		t.FailNow()
+	}
+
+	if event.Payload.Severity != "critical" || event.Payload.Source != "api" || event.Payload.Summary != err.Summary() {
+		t.FailNow()
+	}
+
+	if event.Payload.CustomDetails["userID"] != 310700.0 || event.Payload.CustomDetails["region"] != "eu" {
+		t.FailNow()
+	}
+}
+
+func TestNotifyStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := errorspagerduty.Notifier{Endpoint: server.URL}
+	if err := notifier.Notify(context.Background(), ErrCannotDeposit.Raise()); !errorspagerduty.ErrNotify.Is(err) {
+		t.FailNow()
+	}
+
+	if errorspagerduty.NewEvent(ErrCannotDeposit.Raise()).DedupKey != ErrCannotDeposit.Raise().Hash() {
+		t.FailNow()
+	}
+}
+
+func TestWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	notifier := errorspagerduty.Notifier{Endpoint: server.URL, Timeout: 10 * time.Millisecond}
+	if err := notifier.Write(ErrCannotDeposit.Raise()); !errorspagerduty.ErrNotify.Is(err) {
+		t.FailNow()
+	}
+}
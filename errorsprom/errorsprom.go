@@ -0,0 +1,77 @@
+// Package errorsprom implements a Prometheus counter of errors by type, optionally
+// attaching exemplars to jump from error-rate spikes to representative tracebacks.
+package errorsprom
+
+import (
+	goerrors "errors"
+	"reflect"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/neoxelox/errors"
+)
+
+// Recorder counts errors by kind, package and level in a Prometheus counter. It
+// implements the Prometheus Collector interface to be registered.
+type Recorder struct {
+	counter   *prometheus.CounterVec
+	exemplars bool
+}
+
+// NewRecorder creates a new Recorder of the counter named name and sets to
+// optionally attach the error's hash and trace_id tag as an exemplar to each
+// increment (default is false). Exemplars are only exposed in the OpenMetrics
+// format.
+func NewRecorder(name string, exemplars ...bool) *Recorder {
+	_exemplars := false
+	if len(exemplars) > 0 {
+		_exemplars = exemplars[0]
+	}
+
+	return &Recorder{
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: name,
+			Help: "Number of errors by kind, package and level.",
+		}, []string{"kind", "module", "level"}),
+		exemplars: _exemplars,
+	}
+}
+
+// Record increments the counter of the error's type.
+func (self *Recorder) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	var rerr errors.Error
+	if !goerrors.As(err, &rerr) {
+		self.counter.WithLabelValues(strings.TrimPrefix(reflect.TypeOf(err).String(), "*"), "", "").Inc()
+		return
+	}
+
+	counter := self.counter.WithLabelValues(rerr.Kind(), rerr.Module(), rerr.Level().String())
+
+	adder, ok := counter.(prometheus.ExemplarAdder)
+	if !self.exemplars || !ok {
+		counter.Inc()
+		return
+	}
+
+	exemplar := prometheus.Labels{"error_hash": rerr.Hash()}
+	if traceID, ok := rerr.GetTags()["trace_id"]; ok {
+		exemplar["trace_id"] = traceID
+	}
+
+	adder.AddWithExemplar(1, exemplar)
+}
+
+// Describe implements the Prometheus Collector interface.
+func (self *Recorder) Describe(descs chan<- *prometheus.Desc) {
+	self.counter.Describe(descs)
+}
+
+// Collect implements the Prometheus Collector interface.
+func (self *Recorder) Collect(metrics chan<- prometheus.Metric) {
+	self.counter.Collect(metrics)
+}
@@ -0,0 +1,44 @@
+package errorsprom_test
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errorsprom"
+)
+
+var ErrPaymentFailed = errors.New("payment of %d failed")
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := errorsprom.NewRecorder("app_errors_total", true)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(recorder)
+
+	err := ErrPaymentFailed.Raise(42).Tags(map[string]any{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736"})
+	recorder.Record(err)
+	recorder.Record(ErrPaymentFailed.Raise(7))
+
+	families, gerr := registry.Gather()
+	if gerr != nil || len(families) != 1 || len(families[0].GetMetric()) != 1 {
+		t.FailNow()
+	}
+
+	metric := families[0].GetMetric()[0]
+	if metric.GetCounter().GetValue() != 2 {
+		t.FailNow()
+	}
+
+	exemplar := map[string]string{}
+	for _, label := range metric.GetCounter().GetExemplar().GetLabel() {
+		exemplar[label.GetName()] = label.GetValue()
+	}
+
+	if exemplar["error_hash"] == "" || len(exemplar) != 1 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,88 @@
+// Package errtest implements helpers to test the reports of errors.
+package errtest
+
+import (
+	goerrors "errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var _normalizers = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), "<TIMESTAMP>"},
+	{regexp.MustCompile(`(?:[A-Za-z]:)?[\\/][^\s:]*[\\/]([^\\/\s:]+\.(?:go|s))\b`), "$1"},
+	{regexp.MustCompile(`\.(go|s):\d+`), ".$1:<LINE>"},
+	{regexp.MustCompile(`goroutine \d+`), "goroutine <ID>"},
+	{regexp.MustCompile(`0x[0-9a-fA-F]+`), "<ADDRESS>"},
+}
+
+// Normalize replaces the volatile parts of a report (timestamps, absolute paths,
+// line numbers, goroutine IDs and memory addresses) with stable placeholders.
+func Normalize(report string) string {
+	for _, normalizer := range _normalizers {
+		report = normalizer.pattern.ReplaceAllString(report, normalizer.replacement)
+	}
+
+	return report
+}
+
+func report(err error) string {
+	var rerr errors.Error
+	if goerrors.As(err, &rerr) {
+		return rerr.Report(errors.ReportOptions{All: true})
+	}
+
+	if err == nil {
+		return "<nil>\n"
+	}
+
+	return err.Error() + "\n"
+}
+
+// MatchGolden fails the test when the normalized report of all errors wrapped
+// within the error differs from the golden file. The golden file is written
+// instead when the ERRTEST_UPDATE environment variable is set.
+func MatchGolden(t testing.TB, err error, path string) {
+	t.Helper()
+
+	actual := Normalize(report(err))
+
+	if os.Getenv("ERRTEST_UPDATE") != "" {
+		if werr := os.MkdirAll(filepath.Dir(path), 0o755); werr != nil {
+			t.Fatalf("errtest: cannot create golden file directory: %v", werr)
+		}
+
+		// nolint:gosec
+		if werr := os.WriteFile(path, []byte(actual), 0o644); werr != nil {
+			t.Fatalf("errtest: cannot write golden file: %v", werr)
+		}
+
+		return
+	}
+
+	expected, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatalf("errtest: cannot read golden file (set ERRTEST_UPDATE=1 to create it): %v", rerr)
+	}
+
+	if string(expected) != actual {
+		t.Errorf("errtest: report does not match golden file %s\n--- expected\n%s\n--- actual\n%s",
+			path, expected, actual)
+	}
+}
+
+// AssertIs fails the test when the error is not the target as per errors.Is,
+// such as the Target of a template, explaining the difference between them.
+func AssertIs(t testing.TB, err error, target error) {
+	t.Helper()
+
+	if !goerrors.Is(err, target) {
+		t.Errorf("errtest: error is not the target\n%s", errors.Diff(err, target))
+	}
+}
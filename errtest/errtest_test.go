@@ -0,0 +1,50 @@
+package errtest_test
+
+import (
+	"testing"
+
+	"github.com/neoxelox/errors"
+	"github.com/neoxelox/errors/errtest"
+)
+
+var ErrUserNotFound = errors.New("user %s not found", false)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	report := "/home/ci/src/app/user.go:42 app.GetUser\n" +
+		"goroutine 17 at 2024-05-01T10:20:30.123Z (0xc000123456)"
+
+	expected := "user.go:<LINE> app.GetUser\n" +
+		"goroutine <ID> at <TIMESTAMP> (<ADDRESS>)"
+
+	if errtest.Normalize(report) != expected {
+		t.FailNow()
+	}
+}
+
+func TestMatchGolden(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"user_id": 42})
+
+	errtest.MatchGolden(t, err, "testdata/report.golden")
+}
+
+func TestMatchGoldenExtras(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").
+		Extra(map[string]any{"user_id": 42, "account_id": 7, "region": "eu", "attempt": 3, "plan": "pro"}).
+		Breadcrumb("loading user", map[string]any{"source": "cache", "hit": false, "ttl": 60})
+
+	for i := 0; i < 10; i++ {
+		errtest.MatchGolden(t, err, "testdata/report_extras.golden")
+	}
+}
+
+func TestAssertIs(t *testing.T) {
+	t.Parallel()
+
+	errtest.AssertIs(t, ErrUserNotFound.Raise("Alex"), ErrUserNotFound.Target())
+}
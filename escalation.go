@@ -0,0 +1,84 @@
+package errors
+
+import (
+	goerrors "errors"
+	"sync"
+	"time"
+)
+
+type escalation struct {
+	start time.Time
+	count int
+}
+
+var _escalation = struct {
+	sync.Mutex
+	threshold   int
+	window      time.Duration
+	escalations map[string]*escalation
+}{
+	escalations: make(map[string]*escalation),
+}
+
+// SetEscalation escalates the level of the Errors reported by Report and
+// CaptureSentry one step (warning to error to fatal) for every threshold
+// occurrences with the same hash within each window of time, so chronically
+// failing dependencies end up paging without external threshold services. A
+// threshold of 0 disables the escalation (default).
+func SetEscalation(threshold int, window time.Duration) {
+	_escalation.Lock()
+	defer _escalation.Unlock()
+
+	_escalation.threshold = threshold
+	_escalation.window = window
+	_escalation.escalations = make(map[string]*escalation)
+}
+
+// escalate counts an occurrence of the Error, once, and bumps its level according
+// to the occurrences of its hash within the window, recording the original level
+// and the occurrences into the extra.
+func (self *Error) escalate() {
+	if self.escalated {
+		return
+	}
+
+	self.escalated = true
+
+	_escalation.Lock()
+	defer _escalation.Unlock()
+
+	if _escalation.threshold <= 0 {
+		return
+	}
+
+	hash := self.Hash()
+	now := time.Now()
+
+	occurrences, ok := _escalation.escalations[hash]
+	if !ok || now.Sub(occurrences.start) >= _escalation.window {
+		occurrences = &escalation{start: now}
+		_escalation.escalations[hash] = occurrences
+	}
+
+	occurrences.count++
+
+	level := min(self.level+Level(occurrences.count/_escalation.threshold), LevelFatal)
+	if level == self.level {
+		return
+	}
+
+	self.Extra(map[string]any{
+		"escalated_from": self.level.String(),
+		"occurrences":    occurrences.count,
+	})
+
+	self.level = level
+}
+
+// escalateError escalates the first Error of the chain of an error.
+func escalateError(err error) {
+	var cerr *Error
+	if goerrors.As(err, &cerr) {
+		cerr.escalate()
+	}
+}
@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrFlaky = errors.NewWarning("flaky dependency")
+
+// nolint:paralleltest
+func TestSetEscalation(t *testing.T) {
+	errors.SetEscalation(2, time.Minute)
+	defer errors.SetEscalation(0, 0)
+	defer errors.RemoveSinks()
+
+	var levels []errors.Level
+	errors.AddSink(errors.SinkFunc(func(err error) error {
+		levels = append(levels, err.(*errors.Error).Level()) // nolint:forcetypeassert
+		return nil
+	}), errors.LevelWarning)
+
+	for i := 0; i < 6; i++ {
+		err := ErrFlaky.Raise()
+		if errors.Report(err) != nil || errors.Report(err) != nil {
+			t.FailNow()
+		}
+	}
+
+	expected := []errors.Level{
+		errors.LevelWarning, errors.LevelWarning,
+		errors.LevelError, errors.LevelError,
+		errors.LevelError, errors.LevelError,
+		errors.LevelFatal, errors.LevelFatal,
+		errors.LevelFatal, errors.LevelFatal,
+		errors.LevelFatal, errors.LevelFatal,
+	}
+
+	for i, level := range expected {
+		if levels[i] != level {
+			t.FailNow()
+		}
+	}
+
+	escalated := ErrFlaky.Raise()
+	errors.Report(escalated) // nolint:errcheck
+	if escalated.Extras()["escalated_from"] != "warning" || escalated.Extras()["occurrences"] != 7 {
+		t.FailNow()
+	}
+
+	errors.SetEscalation(0, 0)
+
+	if errors.Report(ErrFlaky.Raise()) != nil || levels[len(levels)-1] != errors.LevelWarning {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const _DEFAULT_EVENT_DEPTH = 3
+
+// flattenField sets the value into the fields under the key, flattening the maps
+// with string keys into dotted keys up to the depth.
+func flattenField(fields map[string]any, key string, value any, depth int) {
+	if value == nil {
+		fields[key] = nil
+		return
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		fields[key] = value
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String || depth <= 0 {
+			fields[key] = fmt.Sprintf("%v", value)
+			return
+		}
+
+		iterator := reflected.MapRange()
+		for iterator.Next() {
+			flattenField(fields, key+"."+iterator.Key().String(), iterator.Value().Interface(), depth-1)
+		}
+	default:
+		fields[key] = fmt.Sprintf("%v", value)
+	}
+}
+
+// EventFields returns the Error flattened into dotted attribute keys, such as
+// error.kind, error.extra.userID or error.stack, for wide-event tooling like
+// Honeycomb or OpenTelemetry span attributes. The extra and tags of the whole
+// chain are merged, the outermost errors winning, and nested maps of the extra
+// are flattened up to the depth, deeper values being stringified (default is 3).
+func (self Error) EventFields(depth ...int) map[string]any {
+	_depth := _DEFAULT_EVENT_DEPTH
+	if len(depth) > 0 {
+		_depth = depth[0]
+	}
+
+	fields := map[string]any{
+		"error.kind":    self.kind,
+		"error.module":  self.module,
+		"error.level":   self.level.String(),
+		"error.message": self.Error(),
+		"error.hash":    self.Hash(),
+	}
+
+	if self.code != "" {
+		fields["error.code"] = self.code
+	}
+
+	if !self.raisedAt.IsZero() {
+		fields["error.raised_at"] = self.raisedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	if self.cause != nil {
+		fields["error.cause"] = self.cause.Error()
+	}
+
+	if len(self.stackTrace) > 0 {
+		stack := make([]string, 0, len(self.stackTrace))
+		for _, frame := range self.stackTrace {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		fields["error.stack"] = strings.Join(stack, "\n")
+	}
+
+	extras := self.AllExtras()
+
+	keys := make([]string, 0, len(extras))
+	for key := range extras {
+		keys = append(keys, key)
+	}
+
+	// Sorted so the flattened keys colliding with others are deterministic
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		flattenField(fields, "error.extra."+key, extras[key], _depth-1)
+	}
+
+	for key, value := range self.AllTags() {
+		fields["error.tag."+key] = value
+	}
+
+	return fields
+}
@@ -0,0 +1,56 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrEventFields = errors.NewWithOptions("cannot charge %s", errors.NewOptions{Code: "CHARGE"})
+
+func TestEventFields(t *testing.T) {
+	t.Parallel()
+
+	err := ErrEventFields.Raise("Alex").
+		Extra(map[string]any{
+			"userID": 310700,
+			"card":   map[string]any{"brand": "visa", "billing": map[string]any{"country": "ES"}},
+		}).
+		Tags(map[string]any{"tenant": "acme"}).
+		Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"userID": 0, "attempt": 2}))
+
+	fields := err.EventFields()
+
+	if fields["error.kind"] != "cannot charge %s" || fields["error.code"] != "CHARGE" || fields["error.level"] != "error" {
+		t.FailNow()
+	}
+
+	if fields["error.message"] != err.Error() || fields["error.cause"] != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if fields["error.extra.userID"] != 310700 || fields["error.extra.attempt"] != 2 || fields["error.tag.tenant"] != "acme" {
+		t.FailNow()
+	}
+
+	if fields["error.extra.card.brand"] != "visa" || fields["error.extra.card.billing.country"] != "ES" {
+		t.FailNow()
+	}
+
+	if stack, ok := fields["error.stack"].(string); !ok || !strings.Contains(stack, "TestEventFields") {
+		t.FailNow()
+	}
+
+	fields = err.EventFields(2)
+	if fields["error.extra.card.brand"] != "visa" || fields["error.extra.card.billing"] != "map[country:ES]" {
+		t.FailNow()
+	}
+
+	fields = err.EventFields(1)
+	if _, ok := fields["error.extra.card"].(string); !ok {
+		t.FailNow()
+	}
+}
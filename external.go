@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+)
+
+type externalMapping struct {
+	match func(error) bool
+	to    Template
+}
+
+var _externalMappings = struct {
+	sync.RWMutex
+	mappings []externalMapping
+}{}
+
+// MapExternal registers the translation of the errors from external libraries
+// matching the function into the Error's type, such as redis.Nil into NotFound,
+// centralizing the translation. Matching errors passed to Cause or Report are
+// replaced with a raised Error of the type wrapping the original error as its
+// cause. The first registered matching translation wins.
+func MapExternal(match func(error) bool, to Template) {
+	_externalMappings.Lock()
+	defer _externalMappings.Unlock()
+
+	_externalMappings.mappings = append(_externalMappings.mappings, externalMapping{match: match, to: to})
+}
+
+// mapExternal returns the foreign error translated as per MapExternal, or the
+// error as is. The skip is the number of frames of the caller to skip.
+func mapExternal(err error, skip int) error {
+	switch err.(type) {
+	case nil, Error, *Error:
+		return err
+	}
+
+	_externalMappings.RLock()
+	defer _externalMappings.RUnlock()
+
+	for _, mapping := range _externalMappings.mappings {
+		if mapping.match(err) {
+			mapped := mapping.to.base().raise(skip+3, mapping.to.base().message)
+			mapped.cause = err
+
+			return mapped
+		}
+	}
+
+	return err
+}
+
+// _wrapperPackages are the packages of generic errors and wrappers, which don't
+// attribute an error to the responsible package.
+var _wrapperPackages = map[string]bool{
+	"":                                   true,
+	"errors":                             true,
+	"fmt":                                true,
+	"github.com/pkg/errors":              true,
+	"github.com/cockroachdb/errors":      true,
+	"golang.org/x/xerrors":               true,
+	"github.com/hashicorp/go-multierror": true,
+}
+
+// foreignPackage returns the package responsible for a foreign error: the package
+// of the outermost error of its chain with a specific type, such as
+// github.com/lib/pq for *pq.Error, or otherwise the package where the innermost
+// stack trace carried by the chain was captured, such as by github.com/pkg/errors.
+func foreignPackage(err error) string {
+	for cause := err; cause != nil; {
+		reflected := reflect.TypeOf(cause)
+		if reflected.Kind() == reflect.Pointer {
+			reflected = reflected.Elem()
+		}
+
+		if !_wrapperPackages[reflected.PkgPath()] {
+			return reflected.PkgPath()
+		}
+
+		switch wrapper := cause.(type) {
+		case interface{ Unwrap() error }:
+			cause = wrapper.Unwrap()
+		case interface{ Cause() error }:
+			cause = wrapper.Cause()
+		default:
+			cause = nil
+		}
+	}
+
+	for _, frame := range foreignStackTrace(err) {
+		if pkg := packagePath(frame.Function); !_wrapperPackages[pkg] {
+			return pkg
+		}
+	}
+
+	return ""
+}
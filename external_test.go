@@ -0,0 +1,62 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrRedisNil = goerrors.New("redis: nil")
+
+func TestMapExternal(t *testing.T) {
+	t.Parallel()
+
+	errors.MapExternal(func(err error) bool {
+		return goerrors.Is(err, ErrRedisNil)
+	}, errors.NotFound)
+
+	err := ErrCannotDeposit.Raise().Cause(ErrRedisNil)
+	if !errors.NotFound.Is(err.Unwrap()) || !goerrors.Is(err, ErrRedisNil) {
+		t.FailNow()
+	}
+
+	stackTrace := err.Unwrap().(*errors.Error).StackTrace()
+	if len(stackTrace) == 0 || !strings.HasSuffix(stackTrace[0].Function, "TestMapExternal") {
+		t.FailNow()
+	}
+
+	if cause := ErrCannotDeposit.Raise().Cause(ErrOtherLibrary).Unwrap(); cause != ErrOtherLibrary {
+		t.FailNow()
+	}
+}
+
+type driverError struct{}
+
+func (driverError) Error() string {
+	return "connection refused"
+}
+
+func TestForeignPackage(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Cause(fmt.Errorf("query: %w", &driverError{}))
+
+	if !strings.Contains(err.Report(errors.ReportOptions{All: true}),
+		"query: connection refused (fmt.wrapError) from package github.com/neoxelox/errors_test\n") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Exception[0].Module != "github.com/neoxelox/errors_test" {
+		t.FailNow()
+	}
+
+	if strings.Contains(ErrCannotDeposit.Raise().Cause(ErrOtherLibrary).Report(errors.ReportOptions{All: true}),
+		"from package") {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// FamilyOptions represents the options shared by the Errors of a family.
+type FamilyOptions struct {
+	// Module overrides the package of the Errors (default is the family's name).
+	Module string
+	// Tags are set on every raised Error of the family, along with a family tag.
+	Tags map[string]any
+}
+
+// ErrorFamily declares Errors of a domain, such as billing or auth, with
+// consistent codes, package and tags, keeping a catalog of them.
+type ErrorFamily struct {
+	name      string
+	module    string
+	tags      map[string]string
+	mutex     sync.RWMutex
+	templates []Template
+}
+
+// Family creates a new family of Errors with a name and optional options.
+func Family(name string, options ...FamilyOptions) *ErrorFamily {
+	_options := FamilyOptions{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	family := &ErrorFamily{
+		name:   name,
+		module: name,
+		tags:   map[string]string{"family": name},
+	}
+
+	if _options.Module != "" {
+		family.module = _options.Module
+	}
+
+	for key, value := range _options.Tags {
+		family.tags[key] = fmt.Sprintf("%v", value)
+	}
+
+	return family
+}
+
+// New creates a new Error of the family with a message (can have a format) and
+// optional options, prefixing its code with the family's name (such as
+// "billing.declined"), and registers it into the family's catalog.
+func (self *ErrorFamily) New(message string, options ...NewOptions) Template {
+	_options := NewOptions{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.Code != "" {
+		_options.Code = self.name + "." + _options.Code
+	}
+
+	_options.Module = self.module
+
+	template := declare(3, message, _options)
+	template.template.tags = maps.Clone(self.tags)
+	_registry.Store(kindKey(template.template), template)
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	self.templates = append(self.templates, template)
+
+	return template
+}
+
+// Name returns the family's name.
+func (self *ErrorFamily) Name() string {
+	return self.name
+}
+
+// Errors returns the catalog of Errors declared within the family, in declaration
+// order, each one linking back to where it was declared (see Template.DeclaredAt),
+// such as to generate the family's error documentation.
+func (self *ErrorFamily) Errors() []Template {
+	self.mutex.RLock()
+	defer self.mutex.RUnlock()
+
+	return append([]Template(nil), self.templates...)
+}
@@ -0,0 +1,42 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var Billing = errors.Family("billing", errors.FamilyOptions{Tags: map[string]any{"team": "payments"}})
+
+var ErrCardDeclined = Billing.New("card declined", errors.NewOptions{Code: "card_declined"})
+
+func TestFamily(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCardDeclined.Raise()
+	if err.Code() != "billing.card_declined" || err.Module() != "billing" {
+		t.FailNow()
+	}
+
+	tags := err.GetTags()
+	if tags["family"] != "billing" || tags["team"] != "payments" {
+		t.FailNow()
+	}
+
+	template, ok := errors.Lookup("billing", "card declined")
+	if !ok || !template.Is(err) {
+		t.FailNow()
+	}
+
+	if catalog := Billing.Errors(); len(catalog) != 1 || !catalog[0].Is(err) {
+		t.FailNow()
+	}
+
+	if !strings.HasSuffix(Billing.Errors()[0].DeclaredAt(), "family_test.go:14") ||
+		template.DeclaredAt() != Billing.Errors()[0].DeclaredAt() {
+		t.FailNow()
+	}
+}
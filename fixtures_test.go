@@ -0,0 +1,42 @@
+package errors_test
+
+import (
+	goerrors "errors"
+
+	"github.com/neoxelox/errors"
+)
+
+// The fixtures shared by the tests of both the default and the headless modes.
+
+var ErrOtherLibrary = goerrors.New("other library error")
+var ErrUserNotFound = errors.New("user %s not found")
+var ErrCannotDeposit = errors.New("cannot deposit")
+
+func view() error {
+	err := usecase()
+	if err != nil {
+		return ErrCannotDeposit.Raise().
+			With("cannot add money to account %s", "ARN3107").Tags(map[string]any{"apiVersion": 2}).Cause(err)
+	}
+
+	return nil
+}
+
+func usecase() error {
+	err := repository()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func repository() error {
+	err := ErrOtherLibrary
+	if err != nil {
+		return ErrUserNotFound.Raise("Alex").
+			Extra(map[string]any{"userID": 310700, "accountID": "ARN3107"}).Cause(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package errors
+
+import (
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// FrameClass represents the origin of a frame of a stack trace.
+type FrameClass int
+
+const (
+	// FrameInApp is the class of the frames of the main module or of the
+	// packages set with SetInAppPrefixes.
+	FrameInApp FrameClass = iota
+	// FrameDependency is the class of the frames of third-party modules.
+	FrameDependency
+	// FrameStdlib is the class of the frames of the standard library.
+	FrameStdlib
+)
+
+// String implements the Stringer interface.
+func (self FrameClass) String() string {
+	switch self {
+	case FrameDependency:
+		return "dependency"
+	case FrameStdlib:
+		return "stdlib"
+	default:
+		return "in-app"
+	}
+}
+
+var _mainModule = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	return info.Main.Path
+})
+
+var _inAppPrefixes = struct {
+	sync.RWMutex
+	prefixes []string
+}{}
+
+// SetInAppPrefixes sets the package prefixes whose frames are classified as
+// in-app besides the ones of the main module, such as the company's shared
+// libraries (default is none).
+func SetInAppPrefixes(prefixes ...string) {
+	_inAppPrefixes.Lock()
+	defer _inAppPrefixes.Unlock()
+
+	_inAppPrefixes.prefixes = append([]string(nil), prefixes...)
+}
+
+func hasPackagePrefix(pkg string, prefix string) bool {
+	return prefix != "" && (pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/"))
+}
+
+// classifyFrame returns the class of the frame of the function.
+func classifyFrame(function string) FrameClass {
+	pkg := strings.TrimSuffix(packagePath(function), "_test")
+
+	if pkg == "main" || hasPackagePrefix(pkg, _mainModule()) {
+		return FrameInApp
+	}
+
+	_inAppPrefixes.RLock()
+	defer _inAppPrefixes.RUnlock()
+
+	for _, prefix := range _inAppPrefixes.prefixes {
+		if hasPackagePrefix(pkg, prefix) {
+			return FrameInApp
+		}
+	}
+
+	if first, _, _ := strings.Cut(pkg, "/"); !strings.Contains(first, ".") {
+		return FrameStdlib
+	}
+
+	return FrameDependency
+}
+
+// trimRuntimeFrames removes the runtime bootstrap frames below main.main and the
+// start of the goroutine, unless there are only those.
+func trimRuntimeFrames(stackTrace []Frame) []Frame {
+	for i, frame := range stackTrace {
+		if frame.Function == "main.main" {
+			return stackTrace[:i+1]
+		}
+	}
+
+	last := len(stackTrace)
+	for last > 1 && (stackTrace[last-1].Function == "runtime.goexit" || stackTrace[last-1].Function == "runtime.main") {
+		last--
+	}
+
+	return stackTrace[:last]
+}
+
+// OnlyInApp is a filter for StackTrace keeping only the in-app frames.
+func OnlyInApp(frame Frame) bool {
+	return frame.Class == FrameInApp
+}
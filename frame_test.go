@@ -0,0 +1,44 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestFrameClass(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise()
+
+	classes := map[errors.FrameClass]bool{}
+	for _, frame := range err.StackTrace() {
+		classes[frame.Class] = true
+
+		if strings.HasPrefix(frame.Function, "testing.") && frame.Class != errors.FrameStdlib {
+			t.FailNow()
+		}
+	}
+
+	if !classes[errors.FrameInApp] || !classes[errors.FrameStdlib] {
+		t.FailNow()
+	}
+
+	inApp := err.StackTrace(errors.OnlyInApp)
+	if len(inApp) == 0 || len(inApp) >= len(err.StackTrace()) || inApp[0].Function != err.StackTrace()[0].Function {
+		t.FailNow()
+	}
+
+	for _, frame := range err.SentryReport().Exception[0].Stacktrace.Frames {
+		if frame.InApp != strings.HasPrefix(frame.Module, "github.com/neoxelox/errors") {
+			t.FailNow()
+		}
+	}
+
+	if !strings.Contains(err.StringReport(), "\x1b[2m") {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const _GCP_EVENT_TYPE = "type.googleapis.com/google.devtools.clouderrorreporting.v1beta1.ReportedErrorEvent"
+
+// GCPOptions represents the options of the Google Cloud Error Reporting events.
+type GCPOptions struct {
+	// Service is the name of the service reporting the errors (default is the
+	// K_SERVICE environment variable set by Cloud Run, or the executable's name).
+	Service string
+	// Version is the version of the service (default is the K_REVISION
+	// environment variable set by Cloud Run, if any).
+	Version string
+}
+
+type gcpServiceContext struct {
+	Service string `json:"service"`
+	Version string `json:"version,omitempty"`
+}
+
+type gcpReportLocation struct {
+	FilePath     string `json:"filePath"`
+	LineNumber   int    `json:"lineNumber"`
+	FunctionName string `json:"functionName"`
+}
+
+type gcpHTTPRequest struct {
+	Method    string `json:"method,omitempty"`
+	URL       string `json:"url,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Referrer  string `json:"referrer,omitempty"`
+	RemoteIP  string `json:"remoteIp,omitempty"`
+}
+
+type gcpContext struct {
+	HTTPRequest    *gcpHTTPRequest    `json:"httpRequest,omitempty"`
+	ReportLocation *gcpReportLocation `json:"reportLocation,omitempty"`
+}
+
+type gcpEvent struct {
+	Type           string            `json:"@type"`
+	EventTime      string            `json:"eventTime,omitempty"`
+	Severity       string            `json:"severity"`
+	ServiceContext gcpServiceContext `json:"serviceContext"`
+	Message        string            `json:"message"`
+	Context        gcpContext        `json:"context"`
+	Labels         map[string]string `json:"logging.googleapis.com/labels,omitempty"`
+}
+
+var _gcpSeverities = map[Level]string{
+	LevelWarning: "WARNING",
+	LevelError:   "ERROR",
+	LevelFatal:   "CRITICAL",
+}
+
+// gcpStack renders the stack trace as the runtime does for panics, the format
+// Google Cloud Error Reporting parses the Go stack traces in.
+func gcpStack(stackTrace []Frame) string {
+	stack := "\n\ngoroutine 1 [running]:\n"
+
+	for _, frame := range stackTrace {
+		stack += frame.Function + "(...)\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + " +0x0\n"
+	}
+
+	return stack
+}
+
+// GCPReport returns the Error as the JSON structured log entry Google Cloud Error
+// Reporting ingests from the logs, with the messages of the whole chain and the
+// stack trace of the first Error formatted as a Go panic, so the errors of GKE or
+// Cloud Run services are grouped without Sentry. The tags are sent as labels.
+func (self Error) GCPReport(options ...GCPOptions) ([]byte, error) {
+	_options := GCPOptions{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	if _options.Service == "" {
+		_options.Service = os.Getenv("K_SERVICE")
+	}
+
+	if _options.Service == "" {
+		if executable, err := os.Executable(); err == nil {
+			_options.Service = executable[strings.LastIndexAny(executable, `/\`)+1:]
+		}
+	}
+
+	if _options.Version == "" {
+		_options.Version = os.Getenv("K_REVISION")
+	}
+
+	event := gcpEvent{
+		Type:     _GCP_EVENT_TYPE,
+		Severity: _gcpSeverities[self.level],
+		ServiceContext: gcpServiceContext{
+			Service: _options.Service,
+			Version: _options.Version,
+		},
+		Message: self.Error(),
+		Labels:  self.tags,
+	}
+
+	if !self.raisedAt.IsZero() {
+		event.EventTime = self.raisedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	if len(self.stackTrace) > 0 {
+		event.Message += gcpStack(self.stackTrace)
+		event.Context.ReportLocation = &gcpReportLocation{
+			FilePath:     self.stackTrace[0].File,
+			LineNumber:   self.stackTrace[0].Line,
+			FunctionName: self.stackTrace[0].Function,
+		}
+	}
+
+	if self.request != nil {
+		event.Context.HTTPRequest = &gcpHTTPRequest{
+			Method:    self.request.Method,
+			URL:       self.request.URL,
+			UserAgent: self.request.Headers["User-Agent"],
+			Referrer:  self.request.Headers["Referer"],
+			RemoteIP:  self.request.Env["REMOTE_ADDR"],
+		}
+	}
+
+	return json.Marshal(event)
+}
@@ -0,0 +1,74 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestGCPReport(t *testing.T) {
+	t.Parallel()
+
+	request := httptest.NewRequest("POST", "https://api.example.com/deposits", nil)
+	request.Header.Set("User-Agent", "curl/8.0")
+
+	err := ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex")).WithRequest(request).
+		Tags(map[string]any{"tenant": "acme"})
+
+	report, gerr := err.GCPReport(errors.GCPOptions{Service: "payments", Version: "v1.2.0"})
+	if gerr != nil {
+		t.FailNow()
+	}
+
+	var event struct {
+		Type           string            `json:"@type"`
+		EventTime      string            `json:"eventTime"`
+		Severity       string            `json:"severity"`
+		ServiceContext map[string]string `json:"serviceContext"`
+		Message        string            `json:"message"`
+		Context        struct {
+			HTTPRequest    map[string]string `json:"httpRequest"`
+			ReportLocation struct {
+				FilePath     string `json:"filePath"`
+				LineNumber   int    `json:"lineNumber"`
+				FunctionName string `json:"functionName"`
+			} `json:"reportLocation"`
+		} `json:"context"`
+		Labels map[string]string `json:"logging.googleapis.com/labels"`
+	}
+
+	if json.Unmarshal(report, &event) != nil {
+		t.FailNow()
+	}
+
+	if !strings.HasSuffix(event.Type, ".ReportedErrorEvent") || event.Severity != "ERROR" || event.EventTime == "" {
+		t.FailNow()
+	}
+
+	if event.ServiceContext["service"] != "payments" || event.ServiceContext["version"] != "v1.2.0" {
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(event.Message, err.Error()+"\n\ngoroutine 1 [running]:\n") ||
+		!strings.Contains(event.Message, "errors_test.TestGCPReport(...)\n\t") {
+		t.FailNow()
+	}
+
+	if !strings.HasSuffix(event.Context.ReportLocation.FilePath, "gcp_test.go") ||
+		event.Context.ReportLocation.FunctionName != "github.com/neoxelox/errors_test.TestGCPReport" {
+		t.FailNow()
+	}
+
+	if event.Context.HTTPRequest["method"] != "POST" || event.Context.HTTPRequest["userAgent"] != "curl/8.0" {
+		t.FailNow()
+	}
+
+	if event.Labels["tenant"] != "acme" {
+		t.FailNow()
+	}
+}
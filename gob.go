@@ -0,0 +1,224 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+	goerrors "errors"
+	"time"
+)
+
+type gobBreadcrumb struct {
+	Timestamp time.Time
+	Message   string
+	Data      map[string]any
+}
+
+type gobAttachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type gobQuery struct {
+	Statement string
+	Args      []string
+}
+
+// gobError is the gob encoding of an Error, as gob only encodes exported fields.
+// Foreign causes are encoded with their messages only, and the detail messages
+// with the DetailCodec.
+type gobError struct {
+	Foreign           bool
+	Kind              string
+	Module            string
+	Code              string
+	DeclaredAt        string
+	Message           string
+	Level             Level
+	Cause             *gobError
+	Extra             map[string]any
+	StackTrace        []Frame
+	ObservedAt        []Frame
+	CaptureStackTrace bool
+	Tags              map[string]string
+	Attachments       []gobAttachment
+	Breadcrumbs       []gobBreadcrumb
+	Payload           any
+	InlineCause       bool
+	SentryEventID     string
+	Op                string
+	GoroutineDump     []byte
+	Request           *httpRequest
+	RaisedAt          time.Time
+	Docs              string
+	Details           [][]byte
+	Query             *gobQuery
+	MemStats          *MemStats
+	Required          []string
+}
+
+func newGobError(err error, depth int) *gobError {
+	var rerr Error
+
+	switch typed := err.(type) {
+	case Error:
+		rerr = typed
+	case *Error:
+		rerr = *typed
+	default:
+		return &gobError{Foreign: true, Message: err.Error()}
+	}
+
+	encoded := &gobError{
+		Kind:              rerr.kind,
+		Module:            rerr.module,
+		Code:              rerr.code,
+		Docs:              rerr.docs,
+		DeclaredAt:        rerr.declaredAt,
+		Message:           rerr.formatted(),
+		Level:             rerr.level,
+		Extra:             rerr.extra,
+		StackTrace:        rerr.stackTrace,
+		ObservedAt:        rerr.observedAt,
+		CaptureStackTrace: rerr.captureStackTrace,
+		Tags:              rerr.tags,
+		Payload:           rerr.payload,
+		InlineCause:       rerr.inlineCause,
+		SentryEventID:     rerr.sentryEventID,
+		Op:                rerr.op,
+		GoroutineDump:     rerr.goroutineDump,
+		Request:           rerr.request,
+		RaisedAt:          rerr.raisedAt,
+		Details:           encodeDetails(rerr.details),
+		MemStats:          rerr.memStats,
+		Required:          rerr.required,
+	}
+
+	if rerr.query != nil {
+		encoded.Query = &gobQuery{Statement: rerr.query.statement, Args: rerr.query.args}
+	}
+
+	for _, attachment := range rerr.attachments {
+		encoded.Attachments = append(encoded.Attachments, gobAttachment{
+			Name:        attachment.name,
+			ContentType: attachment.contentType,
+			Data:        attachment.data,
+		})
+	}
+
+	for _, breadcrumb := range rerr.breadcrumbs {
+		encoded.Breadcrumbs = append(encoded.Breadcrumbs, gobBreadcrumb{
+			Timestamp: breadcrumb.timestamp,
+			Message:   breadcrumb.message,
+			Data:      breadcrumb.data,
+		})
+	}
+
+	if rerr.cause != nil && depth < maxChainDepth() {
+		encoded.Cause = newGobError(rerr.cause, depth+1)
+	}
+
+	return encoded
+}
+
+func (self *gobError) error() error {
+	if self.Foreign {
+		return goerrors.New(self.Message)
+	}
+
+	err := self.decode()
+
+	return &err
+}
+
+func (self *gobError) decode() Error {
+	err := Error{
+		kind:              self.Kind,
+		module:            self.Module,
+		code:              self.Code,
+		docs:              self.Docs,
+		declaredAt:        self.DeclaredAt,
+		message:           self.Message,
+		level:             self.Level,
+		extra:             self.Extra,
+		stackTrace:        self.StackTrace,
+		observedAt:        self.ObservedAt,
+		captureStackTrace: self.CaptureStackTrace,
+		tags:              self.Tags,
+		payload:           self.Payload,
+		inlineCause:       self.InlineCause,
+		sentryEventID:     self.SentryEventID,
+		op:                self.Op,
+		goroutineDump:     self.GoroutineDump,
+		request:           self.Request,
+		raisedAt:          self.RaisedAt,
+		details:           decodeDetails(self.Details),
+		memStats:          self.MemStats,
+		required:          self.Required,
+	}
+
+	if self.Query != nil {
+		err.query = &query{statement: self.Query.Statement, args: self.Query.Args}
+	}
+
+	for _, encoded := range self.Attachments {
+		err.attachments = append(err.attachments, attachment{
+			name:        encoded.Name,
+			contentType: encoded.ContentType,
+			data:        encoded.Data,
+		})
+	}
+
+	for _, encoded := range self.Breadcrumbs {
+		err.breadcrumbs = append(err.breadcrumbs, breadcrumb{
+			timestamp: encoded.Timestamp,
+			message:   encoded.Message,
+			data:      encoded.Data,
+		})
+	}
+
+	if self.Cause != nil {
+		err.cause = self.Cause.error()
+	}
+
+	return err
+}
+
+// GobEncode implements the GobEncoder interface, encoding the Error and all
+// errors wrapped within itself with their stack traces, extra, tags, payloads...
+// so they can be persisted, such as the last error of a failed job, and restored
+// later. The concrete types of the extra, breadcrumb data and payload values must
+// be registered with gob.Register unless they are basic types. Errors not raised
+// by this package are restored as plain errors with their messages.
+func (self Error) GobEncode() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if err := gob.NewEncoder(&buffer).Encode(newGobError(self, 1)); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// GobDecode implements the GobDecoder interface.
+func (self *Error) GobDecode(data []byte) error {
+	var decoded gobError
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+
+	*self = decoded.decode()
+
+	return nil
+}
+
+// MarshalBinary implements the BinaryMarshaler interface with the gob encoding.
+func (self Error) MarshalBinary() ([]byte, error) {
+	return self.GobEncode()
+}
+
+// UnmarshalBinary implements the BinaryUnmarshaler interface with the gob encoding.
+func (self *Error) UnmarshalBinary(data []byte) error {
+	return self.GobDecode(data)
+}
@@ -0,0 +1,77 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	goerrors "errors"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrDeposit = errors.NewOf[depositPayload]("cannot deposit")
+
+type depositPayload struct {
+	Amount int
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	gob.Register(depositPayload{})
+
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"amount": 10}).Tags(map[string]any{"region": "eu"}).
+		Breadcrumb("retrying", map[string]any{"attempt": 2}).Op("usecase.Deposit").
+		Cause(ErrUserNotFound.Raise("Alex").Cause(ErrOtherLibrary))
+	err.Unwrap().(*errors.Error).Attach("body.json", "application/json", []byte(`{}`))
+
+	var buffer bytes.Buffer
+	if gob.NewEncoder(&buffer).Encode(err) != nil {
+		t.FailNow()
+	}
+
+	var decoded *errors.Error
+	if gob.NewDecoder(&buffer).Decode(&decoded) != nil {
+		t.FailNow()
+	}
+
+	if !ErrCannotDeposit.Is(decoded) || !ErrUserNotFound.In(decoded) || decoded.Error() != err.Error() {
+		t.FailNow()
+	}
+
+	if decoded.Extras()["amount"] != 10 || decoded.GetTags()["region"] != "eu" || decoded.OpPath() != "usecase.Deposit" {
+		t.FailNow()
+	}
+
+	if !decoded.RaisedAt().Equal(err.RaisedAt()) || len(decoded.StackTrace()) != len(err.StackTrace()) {
+		t.FailNow()
+	}
+
+	if decoded.StringReport() != err.StringReport() || len(decoded.SentryReport().Attachments) != 1 {
+		t.FailNow()
+	}
+
+	data, merr := ErrDeposit.Raise(depositPayload{Amount: 10}).MarshalBinary()
+	if merr != nil {
+		t.FailNow()
+	}
+
+	var restored errors.Error
+	if restored.UnmarshalBinary(data) != nil {
+		t.FailNow()
+	}
+
+	if payload, ok := errors.PayloadAs[depositPayload](restored); !ok || payload.Amount != 10 {
+		t.FailNow()
+	}
+
+	if _, merr := ErrCannotDeposit.Raise().Extra(map[string]any{"fn": func() {}}).GobEncode(); merr == nil {
+		t.FailNow()
+	}
+
+	if goerrors.Unwrap(goerrors.Unwrap(decoded)).Error() != ErrOtherLibrary.Error() {
+		t.FailNow()
+	}
+}
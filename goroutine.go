@@ -0,0 +1,46 @@
+package errors
+
+var (
+	// ErrPanic is raised when a goroutine started with Go panics.
+	ErrPanic = New("goroutine panicked")
+	// ErrGoroutine wraps errors returned by goroutines started with Go
+	// that were not raised by this package.
+	ErrGoroutine = New("goroutine failed")
+)
+
+// Go runs fn in a new goroutine, recovering any panic into a raised ErrPanic
+// with the full stack trace, and routes both the returned errors and the
+// panics to onErr, so goroutines never crash or fail silently.
+func Go(fn func() error, onErr func(*Error)) {
+	go func() {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			err := ErrPanic.Raise()
+			if cause, ok := recovered.(error); ok {
+				err.Cause(cause)
+			} else {
+				err.With("%v", recovered)
+			}
+
+			onErr(err)
+		}()
+
+		err := fn()
+		if err == nil {
+			return
+		}
+
+		switch err := err.(type) {
+		case *Error:
+			onErr(err)
+		case Error:
+			onErr(&err)
+		default:
+			onErr(ErrGoroutine.Raise().Cause(err))
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	errs := make(chan *errors.Error, 2)
+
+	errors.Go(func() error {
+		panic("boom")
+	}, func(err *errors.Error) { errs <- err })
+
+	err := <-errs
+	if !errors.ErrPanic.Is(err) || err.Error() != "goroutine panicked: boom" {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.StringReport(), "goroutine_test.go") {
+		t.FailNow()
+	}
+
+	errors.Go(func() error {
+		return ErrOtherLibrary
+	}, func(err *errors.Error) { errs <- err })
+
+	err = <-errs
+	if !errors.ErrGoroutine.Is(err) || !err.Has(ErrOtherLibrary) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,58 @@
+//go:build tinygo || errors_headless
+
+package errors
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// The headless mode, selected under TinyGo or with the errors_headless build tag,
+// drops the dependencies on sentry-go and on the runtime stack unwinding that
+// TinyGo and some WASM targets lack. The Errors work the same except that they
+// carry no stack traces nor goroutine dumps and cannot be reported to Sentry. As
+// the packages declaring the templates can't be detected either, the templates
+// should be declared with NewOptions.Module or WithModule, otherwise each one
+// gets a distinct unknown module, such as "unknown#3", so the templates with the
+// same message in different packages remain distinct types.
+
+// callers captures no program counters in headless mode.
+func callers(_ int, _ []uintptr) int {
+	return 0
+}
+
+var _unknownModules atomic.Int64
+
+// unknownModule returns a distinct module for each template declared without
+// NewOptions.Module in headless mode, numbered in declaration order.
+func unknownModule() string {
+	return "unknown#" + strconv.FormatInt(_unknownModules.Add(1), 10)
+}
+
+// goroutineDump captures no stack traces in headless mode.
+func goroutineDump() []byte {
+	return nil
+}
+
+// SentryEventOptions represents the options to build the Sentry Event of an
+// Error, which are ignored in headless mode.
+type SentryEventOptions struct {
+	// Concise sets the message of the event to the Error's message, moving the
+	// full string report into the extra.
+	Concise bool
+	// Environment is the environment of the event, such as production.
+	Environment string
+	// Release is the release of the event, such as the version of the service.
+	Release string
+	// SampleRate is the rate (from 0.0 to 1.0) at which events are built.
+	SampleRate float64
+}
+
+// SetSentryEventOptions does nothing in headless mode.
+func SetSentryEventOptions(_ SentryEventOptions) {}
+
+// useSentryProfile does nothing in headless mode.
+func useSentryProfile(_ SentryEventOptions) {}
+
+// SetSentryContextLines does nothing in headless mode.
+func SetSentryContextLines(_ int) {}
@@ -0,0 +1,52 @@
+//go:build tinygo || errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var (
+	ErrHeadlessFirst  = errors.New("headless error")
+	ErrHeadlessSecond = errors.New("headless error")
+	ErrHeadlessModule = errors.NewWithOptions("headless error", errors.NewOptions{Module: "billing"})
+	ErrHeadlessFatal  = errors.NewWithOptions("headless deadlock", errors.NewOptions{Level: errors.LevelFatal})
+)
+
+func TestHeadless(t *testing.T) {
+	t.Parallel()
+
+	err := ErrHeadlessFirst.Raise()
+	if err.Error() != "headless error" || len(err.StackTrace()) != 0 {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.Report(errors.ReportOptions{}), "(Stack trace not available)") {
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(ErrHeadlessFirst.Module(), "unknown#") || ErrHeadlessFirst.Module() == ErrHeadlessSecond.Module() {
+		t.FailNow()
+	}
+
+	if ErrHeadlessSecond.Is(err) || ErrHeadlessModule.Module() != "billing" {
+		t.FailNow()
+	}
+
+	if ErrHeadlessFirst.WithModule("billing").Module() != "billing" {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestHeadlessGoroutineDump(t *testing.T) {
+	errors.SetGoroutineDump(true)
+	defer errors.SetGoroutineDump(false)
+
+	if ErrHeadlessFatal.Raise().GoroutineDump() != "" {
+		t.FailNow()
+	}
+}
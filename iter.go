@@ -0,0 +1,41 @@
+//go:build go1.23
+
+package errors
+
+import (
+	"iter"
+)
+
+// Frames returns an iterator over the frames of the raised Error's stack trace,
+// starting from the most recent call.
+func (self Error) Frames() iter.Seq[Frame] {
+	return func(yield func(Frame) bool) {
+		for _, frame := range self.stackTrace {
+			if !yield(frame) {
+				return
+			}
+		}
+	}
+}
+
+// Causes returns an iterator over the errors wrapped within the Error itself,
+// starting from the outermost.
+func (self Error) Causes() iter.Seq[error] {
+	return func(yield func(error) bool) {
+		cause := self.cause
+		for depth := 1; cause != nil && depth < maxChainDepth(); depth++ {
+			if !yield(cause) {
+				return
+			}
+
+			switch err := cause.(type) {
+			case Error:
+				cause = err.cause
+			case *Error:
+				cause = err.cause
+			default:
+				cause = nil
+			}
+		}
+	}
+}
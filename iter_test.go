@@ -0,0 +1,53 @@
+//go:build go1.23
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestIterators(t *testing.T) {
+	t.Parallel()
+
+	err := view()
+
+	cerr, ok := err.(*errors.Error)
+	if !ok {
+		t.FailNow()
+	}
+
+	for frame := range cerr.Frames() {
+		if !strings.HasSuffix(frame.Function, ".view") {
+			t.FailNow()
+		}
+
+		break
+	}
+
+	causes := make([]error, 0, 2)
+	for cause := range cerr.Causes() {
+		causes = append(causes, cause)
+	}
+
+	if len(causes) != 2 || !ErrUserNotFound.Is(causes[0]) || causes[1] != ErrOtherLibrary {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestCausesMaxChainDepth(t *testing.T) {
+	errors.SetMaxChainDepth(2)
+	defer errors.SetMaxChainDepth(64)
+
+	causes := 0
+	for range ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex").Cause(ErrOtherLibrary)).Causes() {
+		causes++
+	}
+
+	if causes != 1 {
+		t.FailNow()
+	}
+}
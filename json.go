@@ -0,0 +1,168 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// JSONMode represents how MarshalJSON encodes the Errors.
+type JSONMode int32
+
+const (
+	// JSONMessage encodes the Errors as their message strings.
+	JSONMessage JSONMode = iota
+	// JSONStructured encodes the Errors as the verbose structured JSON of JSON.
+	JSONStructured
+)
+
+var _jsonMode = &atomic.Int32{}
+
+// SetJSONMode sets how MarshalJSON encodes the Errors process-wide, so rich
+// structured logs can be enabled without changing every encoder call (default
+// is JSONMessage).
+func SetJSONMode(mode JSONMode) {
+	_jsonMode.Store(int32(mode))
+}
+
+// JSONOptions represents the options to encode an Error into structured JSON.
+type JSONOptions struct {
+	// Verbose includes the raise times, stack traces, extra, tags, detail messages
+	// and attachments (base64 encoded) of the errors besides their types,
+	// packages, codes, levels and messages.
+	Verbose bool
+}
+
+type jsonFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+type jsonAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type,omitempty"`
+	Data        []byte `json:"data"`
+}
+
+// jsonError is the structured JSON encoding of an Error. Its fields are encoded
+// in declaration order and its maps sorted by key, so encodings are byte-stable.
+type jsonError struct {
+	Kind        string            `json:"kind,omitempty"`
+	Module      string            `json:"module,omitempty"`
+	Code        string            `json:"code,omitempty"`
+	Level       string            `json:"level,omitempty"`
+	Docs        string            `json:"docs,omitempty"`
+	Message     string            `json:"message"`
+	RaisedAt    string            `json:"raised_at,omitempty"`
+	Stack       []jsonFrame       `json:"stack,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Details     []json.RawMessage `json:"details,omitempty"`
+	Attachments []jsonAttachment  `json:"attachments,omitempty"`
+	Cause       *jsonError        `json:"cause,omitempty"`
+}
+
+// jsonValue returns the value if it can be encoded into JSON, or its string
+// representation otherwise.
+func jsonValue(value any) any {
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return value
+}
+
+func newJSONError(err error, options JSONOptions, depth int) *jsonError {
+	var rerr Error
+
+	switch typed := err.(type) {
+	case Error:
+		rerr = typed
+	case *Error:
+		rerr = *typed
+	default:
+		return &jsonError{
+			Kind:    strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+			Module:  foreignPackage(err),
+			Message: err.Error(),
+		}
+	}
+
+	encoded := &jsonError{
+		Kind:    rerr.kind,
+		Module:  rerr.module,
+		Code:    rerr.code,
+		Level:   rerr.level.String(),
+		Docs:    rerr.docs,
+		Message: rerr.formatted(),
+	}
+
+	if options.Verbose {
+		if !rerr.raisedAt.IsZero() {
+			encoded.RaisedAt = rerr.raisedAt.UTC().Format(time.RFC3339Nano)
+		}
+
+		for _, frame := range rerr.stackTrace {
+			encoded.Stack = append(encoded.Stack, jsonFrame{File: frame.File, Line: frame.Line, Function: frame.Function})
+		}
+
+		if len(rerr.extra) > 0 {
+			encoded.Extra = make(map[string]any, len(rerr.extra))
+			for key, value := range rerr.extra {
+				encoded.Extra[key] = jsonValue(value)
+			}
+		}
+
+		if len(rerr.tags) > 0 {
+			encoded.Tags = rerr.tags
+		}
+
+		encoded.Details = jsonDetails(rerr.details)
+
+		for _, attachment := range rerr.attachments {
+			encoded.Attachments = append(encoded.Attachments, jsonAttachment{
+				Name:        attachment.name,
+				ContentType: attachment.contentType,
+				Data:        attachment.data,
+			})
+		}
+	}
+
+	if rerr.cause != nil && depth < maxChainDepth() {
+		encoded.Cause = newJSONError(rerr.cause, options, depth+1)
+	}
+
+	return encoded
+}
+
+type jsonMarshaler struct {
+	err     error
+	options JSONOptions
+}
+
+// MarshalJSON implements the JSONMarshaler interface.
+func (self jsonMarshaler) MarshalJSON() ([]byte, error) {
+	if self.err == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(newJSONError(self.err, self.options, 1))
+}
+
+// JSON returns a JSONMarshaler encoding the error and all errors wrapped within
+// itself into structured JSON according to the options, with a stable field
+// order and without empty fields, so the encodings are byte-stable for contract
+// tests and content hashes.
+func JSON(err error, options JSONOptions) json.Marshaler {
+	return jsonMarshaler{err: err, options: options}
+}
+
+// Detailed returns a JSONMarshaler encoding the error and all errors wrapped
+// within itself into verbose structured JSON regardless of the JSON mode.
+func Detailed(err error) json.Marshaler {
+	return JSON(err, JSONOptions{Verbose: true})
+}
@@ -0,0 +1,105 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"b": 2, "a": 1, "fn": func() {}}).
+		Cause(ErrUserNotFound.Raise(`"Alex"`).Cause(ErrOtherLibrary))
+
+	compact, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{}))
+	if jerr != nil {
+		t.FailNow()
+	}
+
+	expected := `{"kind":"cannot deposit","module":"github.com/neoxelox/errors_test","level":"error",` +
+		`"message":"cannot deposit","cause":{"kind":"user %s not found","module":"github.com/neoxelox/errors_test",` +
+		`"level":"error","message":"user \"Alex\" not found","cause":{"kind":"errors.errorString",` +
+		`"message":"other library error"}}}`
+	if string(compact) != expected {
+		t.FailNow()
+	}
+
+	verbose, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if jerr != nil || !strings.Contains(string(verbose), `"extra":{"a":1,"b":2,"fn":"0x`) {
+		t.FailNow()
+	}
+
+	again, _ := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if string(again) != string(verbose) || !strings.Contains(string(verbose), `"stack":[{"file":`) {
+		t.FailNow()
+	}
+
+	if message, _ := json.Marshal(err.Unwrap()); string(message) != `"user \"Alex\" not found: other library error"` {
+		t.FailNow()
+	}
+
+	if null, _ := json.Marshal(errors.JSON(nil, errors.JSONOptions{})); string(null) != "null" {
+		t.FailNow()
+	}
+}
+
+func TestJSONAttachments(t *testing.T) {
+	t.Parallel()
+
+	data := []byte{0x00, 0xff, 'S', 'Q', 'L'}
+	err := ErrCannotDeposit.Raise().
+		Cause(ErrUserNotFound.Raise("Alex").Attach("query.bin", "application/octet-stream", data))
+
+	verbose, jerr := json.Marshal(errors.JSON(err, errors.JSONOptions{Verbose: true}))
+	if jerr != nil || !strings.Contains(string(verbose),
+		`"attachments":[{"name":"query.bin","content_type":"application/octet-stream","data":"AP9TUUw="}]`) {
+		t.FailNow()
+	}
+
+	var decoded struct {
+		Cause struct {
+			Attachments []struct {
+				Name        string `json:"name"`
+				ContentType string `json:"content_type"`
+				Data        []byte `json:"data"`
+			} `json:"attachments"`
+		} `json:"cause"`
+	}
+
+	if json.Unmarshal(verbose, &decoded) != nil || len(decoded.Cause.Attachments) != 1 ||
+		decoded.Cause.Attachments[0].Name != "query.bin" || string(decoded.Cause.Attachments[0].Data) != string(data) {
+		t.FailNow()
+	}
+
+	compact, _ := json.Marshal(errors.JSON(err, errors.JSONOptions{}))
+	if strings.Contains(string(compact), "attachments") {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestJSONMode(t *testing.T) {
+	err := ErrCannotDeposit.Raise().Extra(map[string]any{"amount": 10})
+
+	detailed, _ := json.Marshal(errors.Detailed(err))
+	if message, _ := json.Marshal(err); string(message) != `"cannot deposit"` {
+		t.FailNow()
+	}
+
+	errors.SetJSONMode(errors.JSONStructured)
+	defer errors.SetJSONMode(errors.JSONMessage)
+
+	structured, jerr := json.Marshal(map[string]any{"error": err})
+	if jerr != nil || string(structured) != `{"error":`+string(detailed)+`}` {
+		t.FailNow()
+	}
+
+	if !strings.Contains(string(detailed), `"extra":{"amount":10}`) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	goerrors "errors"
+	"sync"
+	"time"
+)
+
+type reportLimit struct {
+	limit      int
+	window     time.Duration
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+var _reportLimits = struct {
+	sync.Mutex
+	limits map[string]*reportLimit
+}{
+	limits: make(map[string]*reportLimit),
+}
+
+// SetReportLimit limits the number of Errors of the template's type reported by
+// Report and CaptureSentry within each window of time. Exceeding occurrences are
+// counted and attached to the next allowed Sentry report.
+func SetReportLimit(template Template, limit int, window time.Duration) {
+	_reportLimits.Lock()
+	defer _reportLimits.Unlock()
+
+	_reportLimits.limits[kindKey(template.base())] = &reportLimit{
+		limit:  limit,
+		window: window,
+	}
+}
+
+// reportAllowed returns whether the Error can be reported and how many
+// occurrences of its type were suppressed since the last report.
+func reportAllowed(err Error) (bool, int) {
+	_reportLimits.Lock()
+	defer _reportLimits.Unlock()
+
+	limit, ok := _reportLimits.limits[kindKey(&err)]
+	if !ok {
+		return true, 0
+	}
+
+	now := time.Now()
+	if now.Sub(limit.start) >= limit.window {
+		limit.start = now
+		limit.count = 0
+	}
+
+	if limit.count >= limit.limit {
+		limit.suppressed++
+		return false, 0
+	}
+
+	limit.count++
+	suppressed := limit.suppressed
+	limit.suppressed = 0
+
+	return true, suppressed
+}
+
+// allowReport applies the report limit of the Error's type once, so reporting it
+// with Report to the SentrySink counts it a single time.
+func (self *Error) allowReport() bool {
+	if self.limited {
+		return self.suppressed >= 0
+	}
+
+	self.limited = true
+
+	allowed, suppressed := reportAllowed(*self)
+	if !allowed {
+		self.suppressed = -1
+		return false
+	}
+
+	self.suppressed = suppressed
+
+	return true
+}
+
+// limitError applies the report limit to the first Error of the chain of an error.
+func limitError(err error) bool {
+	var cerr *Error
+	if goerrors.As(err, &cerr) {
+		return cerr.allowReport()
+	}
+
+	var rerr Error
+	if goerrors.As(err, &rerr) {
+		allowed, _ := reportAllowed(rerr)
+		return allowed
+	}
+
+	return true
+}
@@ -0,0 +1,46 @@
+package errors
+
+import (
+	goerrors "errors"
+)
+
+// IsAny checks whether an error is of the type of any of the templates, as
+// calling Is on each of them would, so transport layers can match several
+// domain Errors at once.
+func IsAny(err error, templates ...Template) bool {
+	for _, template := range templates {
+		if template.Is(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasAny checks whether an Error of the type of any of the templates is the
+// error itself or is wrapped inside it, walking the chains of Errors as Has does
+// and the chains of foreign errors as the standard library's errors.Is does.
+func HasAny(err error, templates ...Template) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, template := range templates {
+		switch typed := err.(type) {
+		case Error:
+			if typed.Has(template.Target()) {
+				return true
+			}
+		case *Error:
+			if typed.Has(template.Target()) {
+				return true
+			}
+		default:
+			if goerrors.Is(err, template.Target()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,46 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var (
+	ErrMatchA = errors.New("match a")
+	ErrMatchB = errors.New("match b")
+	ErrMatchC = errors.New("match c")
+)
+
+func TestIsAny(t *testing.T) {
+	t.Parallel()
+
+	err := ErrMatchB.Raise().Cause(ErrMatchC.Raise())
+
+	if !errors.IsAny(err, ErrMatchA, ErrMatchB) {
+		t.FailNow()
+	}
+
+	if errors.IsAny(err, ErrMatchA, ErrMatchC) || errors.IsAny(err) || errors.IsAny(nil, ErrMatchA) {
+		t.FailNow()
+	}
+}
+
+func TestHasAny(t *testing.T) {
+	t.Parallel()
+
+	err := ErrMatchB.Raise().Cause(ErrMatchC.Raise())
+
+	if !errors.HasAny(err, ErrMatchA, ErrMatchC) || !errors.HasAny(*err, ErrMatchB) {
+		t.FailNow()
+	}
+
+	if !errors.HasAny(fmt.Errorf("wrapped: %w", err), ErrMatchA, ErrMatchC) {
+		t.FailNow()
+	}
+
+	if errors.HasAny(err, ErrMatchA) || errors.HasAny(nil, ErrMatchA) || errors.HasAny(fmt.Errorf("foreign")) {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// MemStats represents a snapshot of the runtime memory statistics taken when an
+// Error was raised.
+type MemStats struct {
+	// HeapAlloc is the number of bytes of allocated heap objects.
+	HeapAlloc uint64
+	// HeapSys is the number of bytes of heap memory obtained from the OS.
+	HeapSys uint64
+	// HeapObjects is the number of allocated heap objects.
+	HeapObjects uint64
+	// Sys is the total number of bytes of memory obtained from the OS.
+	Sys uint64
+	// Goroutines is the number of goroutines that exist.
+	Goroutines int
+	// NumGC is the number of completed GC cycles.
+	NumGC uint32
+	// LastPause is the duration of the last GC stop-the-world pause.
+	LastPause time.Duration
+	// TotalPause is the cumulative duration of the GC stop-the-world pauses.
+	TotalPause time.Duration
+	// GCCPUFraction is the fraction of the CPU time used by the GC.
+	GCCPUFraction float64
+}
+
+var _memStatsKinds atomic.Pointer[map[string]bool]
+
+// SetMemStats sets the types of the Errors, such as the ones of out of memory or
+// too many open files failures, that snapshot the runtime memory statistics when
+// raised, to diagnose failures caused by memory pressure (default is none). Reading them briefly stops the world.
+func SetMemStats(templates ...Template) {
+	if len(templates) == 0 {
+		_memStatsKinds.Store(nil)
+		return
+	}
+
+	kinds := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		kinds[kindKey(template.base())] = true
+	}
+
+	_memStatsKinds.Store(&kinds)
+}
+
+// memStats returns a snapshot of the runtime memory statistics if enabled for the
+// type of the Error.
+func memStats(err *Error) *MemStats {
+	kinds := _memStatsKinds.Load()
+	if kinds == nil || !(*kinds)[kindKey(err)] {
+		return nil
+	}
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	return &MemStats{
+		HeapAlloc:     stats.HeapAlloc,
+		HeapSys:       stats.HeapSys,
+		HeapObjects:   stats.HeapObjects,
+		Sys:           stats.Sys,
+		Goroutines:    runtime.NumGoroutine(),
+		NumGC:         stats.NumGC,
+		LastPause:     time.Duration(stats.PauseNs[(stats.NumGC+255)%256]),
+		TotalPause:    time.Duration(stats.PauseTotalNs),
+		GCCPUFraction: stats.GCCPUFraction,
+	}
+}
+
+// MemStats returns the runtime memory statistics snapshotted when the Error was
+// raised, if enabled for its type with SetMemStats.
+func (self Error) MemStats() *MemStats {
+	return self.memStats
+}
+
+// formatBytes formats the number of bytes with a binary unit, such as 12.3MiB.
+func formatBytes(bytes uint64) string {
+	const unit = 1024
+	if bytes < unit {
+		return strconv.FormatUint(bytes, 10) + "B"
+	}
+
+	div, exp := uint64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// String implements the Stringer interface, rendering the statistics compactly.
+func (self MemStats) String() string {
+	return fmt.Sprintf("heap=%s/%s objects=%d sys=%s goroutines=%d gc=%d last_pause=%s total_pause=%s gc_cpu=%.2f%%",
+		formatBytes(self.HeapAlloc), formatBytes(self.HeapSys), self.HeapObjects, formatBytes(self.Sys),
+		self.Goroutines, self.NumGC, self.LastPause, self.TotalPause, 100*self.GCCPUFraction)
+}
@@ -0,0 +1,73 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var (
+	ErrOutOfMemory  = errors.New("out of memory")
+	ErrTooManyFiles = errors.New("too many open files")
+)
+
+// nolint:paralleltest
+func TestSetMemStats(t *testing.T) {
+	defer errors.SetMemStats()
+
+	errors.SetMemStats(ErrTooManyFiles, ErrOutOfMemory)
+
+	err := ErrOutOfMemory.Raise()
+
+	stats := err.MemStats()
+	if stats == nil || stats.HeapAlloc == 0 || stats.Sys == 0 || stats.Goroutines == 0 {
+		t.FailNow()
+	}
+
+	report := err.Report(errors.ReportOptions{})
+	if !strings.Contains(report, "    Memory: heap=") || !strings.Contains(report, " goroutines=") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Extra["memstats"] != stats.String() {
+		t.FailNow()
+	}
+
+	data, _ := err.MarshalBinary()
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || decoded.MemStats() == nil || *decoded.MemStats() != *stats {
+		t.FailNow()
+	}
+
+	if ErrTooManyFiles.Raise().MemStats() == nil || ErrUserNotFound.Raise("Alex").MemStats() != nil {
+		t.FailNow()
+	}
+
+	errors.SetMemStats()
+
+	if ErrOutOfMemory.Raise().MemStats() != nil {
+		t.FailNow()
+	}
+}
+
+func TestMemStatsString(t *testing.T) {
+	t.Parallel()
+
+	stats := errors.MemStats{
+		HeapAlloc:   12 << 20,
+		HeapSys:     64 << 20,
+		HeapObjects: 1500,
+		Sys:         1536,
+		Goroutines:  42,
+		NumGC:       7,
+	}
+
+	if stats.String() != "heap=12.0MiB/64.0MiB objects=1500 sys=1.5KiB goroutines=42 gc=7 last_pause=0s "+
+		"total_pause=0s gc_cpu=0.00%" {
+		t.FailNow()
+	}
+}
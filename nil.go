@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"reflect"
+)
+
+// IsNil checks whether an error is nil, including interfaces holding a nil
+// pointer such as a nil *Error, the classic typed-nil bug.
+func IsNil(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(err)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// OrNil returns the raised Error as an error interface, or a true nil error if it
+// is nil, so functions returning a possibly nil *Error as an error don't return
+// a non-nil interface holding a nil pointer.
+func (self *Error) OrNil() error {
+	if self == nil {
+		return nil
+	}
+
+	return self
+}
@@ -0,0 +1,37 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func mayFail(fail bool) *errors.Error {
+	if fail {
+		return ErrCannotDeposit.Raise()
+	}
+
+	return nil
+}
+
+func TestNil(t *testing.T) {
+	t.Parallel()
+
+	var err error = mayFail(false)
+	if err == nil || !errors.IsNil(err) {
+		t.FailNow()
+	}
+
+	if mayFail(false).OrNil() != nil || mayFail(true).OrNil() == nil {
+		t.FailNow()
+	}
+
+	if errors.IsNil(mayFail(true)) || !errors.IsNil(nil) {
+		t.FailNow()
+	}
+
+	if fmt.Sprintf("%s %v %+v %q", mayFail(false), err, err, err) != "<nil> <nil> <nil> <nil>" {
+		t.FailNow()
+	}
+}
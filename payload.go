@@ -0,0 +1,53 @@
+package errors
+
+// Of represents an Error carrying a strongly-typed payload when raised, for
+// domains that want compile-time checked error data instead of extra.
+type Of[T any] struct {
+	Template
+}
+
+// NewOf creates a new Error carrying a payload of type T with a message (can have
+// a format) and sets to optionally capture the stack trace when raised (default
+// is true).
+func NewOf[T any](message string, captureStackTrace ...bool) Of[T] {
+	_captureStackTrace := true
+	if len(captureStackTrace) > 0 {
+		_captureStackTrace = captureStackTrace[0]
+	}
+
+	return Of[T]{Template: declare(3, message, NewOptions{DisableStack: !_captureStackTrace})}
+}
+
+// Raise creates a new Error instance formatting its message if needed, capturing
+// the stack trace if enabled and attaching the payload.
+func (self Of[T]) Raise(payload T, args ...any) *Error {
+	err := self.base().raise(3, sprintf(self.base().message, args))
+	err.payload = payload
+
+	return err
+}
+
+// PayloadAs returns the payload of the first Error of the chain that carries a
+// payload of type T, and whether it was found.
+func PayloadAs[T any](err error) (T, bool) {
+	for err != nil {
+		var payload any
+
+		switch cerr := err.(type) {
+		case Error:
+			payload, err = cerr.payload, cerr.cause
+		case *Error:
+			payload, err = cerr.payload, cerr.cause
+		default:
+			err = nil
+		}
+
+		if typed, ok := payload.(T); ok {
+			return typed, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
@@ -0,0 +1,45 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+type InsufficientFunds struct {
+	Balance int
+	Amount  int
+}
+
+var ErrInsufficientFunds = errors.NewOf[InsufficientFunds]("insufficient funds for %s")
+
+func TestPayload(t *testing.T) {
+	t.Parallel()
+
+	err := ErrCannotDeposit.Raise().Cause(ErrInsufficientFunds.Raise(InsufficientFunds{Balance: 10, Amount: 42}, "Alex"))
+
+	raised := ErrInsufficientFunds.Raise(InsufficientFunds{}, "Bob")
+	if !strings.HasSuffix(raised.StackTrace()[0].Function, ".TestPayload") {
+		t.FailNow()
+	}
+
+	payload, ok := errors.PayloadAs[InsufficientFunds](err)
+	if !ok || payload.Balance != 10 || payload.Amount != 42 {
+		t.FailNow()
+	}
+
+	if _, ok := errors.PayloadAs[string](err); ok {
+		t.FailNow()
+	}
+
+	if !ErrInsufficientFunds.In(err) || !strings.Contains(err.StringReport(), "payload={Balance:10 Amount:42}") {
+		t.FailNow()
+	}
+
+	if err.Error() != "cannot deposit: insufficient funds for Alex" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+var _processTags atomic.Pointer[map[string]string]
+
+// SetProcessTags sets the tags of every raised Error, such as the region or the
+// version of the service, overridden by the tags of the Errors themselves. Nil
+// tags remove them.
+func SetProcessTags(tags map[string]any) {
+	if len(tags) == 0 {
+		_processTags.Store(nil)
+		return
+	}
+
+	processTags := make(map[string]string, len(tags))
+	for key, value := range tags {
+		processTags[key] = fmt.Sprintf("%v", value)
+	}
+
+	_processTags.Store(&processTags)
+}
+
+// withProcessTags returns the tags of an Error merged over the process tags.
+func withProcessTags(tags map[string]string) map[string]string {
+	processTags := _processTags.Load()
+	if processTags == nil {
+		return maps.Clone(tags)
+	}
+
+	merged := maps.Clone(*processTags)
+	maps.Copy(merged, tags)
+
+	return merged
+}
+
+// UseKubernetesTags adds the metadata of the pod exposed through the downward API
+// as process tags (k8s.pod, k8s.namespace, k8s.node, k8s.image and k8s.image_tag),
+// read from the POD_NAME, POD_NAMESPACE (or NAMESPACE), NODE_NAME and
+// CONTAINER_IMAGE environment variables, so every report is attributable to its
+// pod. The missing variables are skipped.
+func UseKubernetesTags() {
+	tags := make(map[string]any)
+
+	if processTags := _processTags.Load(); processTags != nil {
+		for key, value := range *processTags {
+			if !strings.HasPrefix(key, "k8s.") {
+				tags[key] = value
+			}
+		}
+	}
+
+	lookup := func(tag string, variables ...string) {
+		for _, variable := range variables {
+			if value := os.Getenv(variable); value != "" {
+				tags[tag] = value
+				return
+			}
+		}
+	}
+
+	lookup("k8s.pod", "POD_NAME")
+	lookup("k8s.namespace", "POD_NAMESPACE", "NAMESPACE")
+	lookup("k8s.node", "NODE_NAME")
+	lookup("k8s.image", "CONTAINER_IMAGE")
+
+	if image, ok := tags["k8s.image"].(string); ok {
+		image, _, _ = strings.Cut(image, "@")
+		if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+			tags["k8s.image_tag"] = image[colon+1:]
+		}
+	}
+
+	SetProcessTags(tags)
+}
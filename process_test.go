@@ -0,0 +1,57 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+// nolint:paralleltest
+func TestSetProcessTags(t *testing.T) {
+	errors.SetProcessTags(map[string]any{"region": "eu-west-1", "family": "process"})
+	defer errors.SetProcessTags(nil)
+
+	tags := ErrCannotDeposit.Raise().GetTags()
+	if tags["region"] != "eu-west-1" || tags["family"] != "process" {
+		t.FailNow()
+	}
+
+	family := errors.Family("payments")
+	if family.New("declined").Raise().GetTags()["family"] != "payments" {
+		t.FailNow()
+	}
+
+	errors.SetProcessTags(nil)
+
+	if _, ok := ErrCannotDeposit.Raise().GetTags()["region"]; ok {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestUseKubernetesTags(t *testing.T) {
+	t.Setenv("POD_NAME", "api-7d9f8-x2x4z")
+	t.Setenv("NAMESPACE", "production")
+	t.Setenv("NODE_NAME", "node-1")
+	t.Setenv("CONTAINER_IMAGE", "registry.example.com:5000/api:v1.4.2@sha256:abc")
+
+	errors.SetProcessTags(map[string]any{"region": "eu-west-1"})
+	errors.UseKubernetesTags()
+	defer errors.SetProcessTags(nil)
+
+	tags := ErrCannotDeposit.Raise().GetTags()
+	if tags["k8s.pod"] != "api-7d9f8-x2x4z" || tags["k8s.namespace"] != "production" || tags["k8s.node"] != "node-1" {
+		t.FailNow()
+	}
+
+	if tags["k8s.image_tag"] != "v1.4.2" || tags["region"] != "eu-west-1" {
+		t.FailNow()
+	}
+
+	t.Setenv("CONTAINER_IMAGE", "registry.example.com:5000/api")
+	errors.UseKubernetesTags()
+
+	if _, ok := ErrCannotDeposit.Raise().GetTags()["k8s.image_tag"]; ok {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Profile represents a preset of the process-wide behavior of the errors,
+// applied at once with UseProfile.
+type Profile struct {
+	// Color renders StringReport with ANSI colors.
+	Color bool
+	// SourceContext renders that number of source code lines around each frame
+	// in StringReport and ReportTo (0 means none).
+	SourceContext int
+	// MaxFrames limits the number of rendered frames of each stack trace in
+	// StringReport and ReportTo (0 means unlimited).
+	MaxFrames int
+	// StackCapture captures the stack traces of the raised Errors (see
+	// SetStackCapture).
+	StackCapture bool
+	// MessageMasking hides the messages of the wrapped errors from the API bodies
+	// (see SetMessageMasking).
+	MessageMasking bool
+	// QueryMasking masks the arguments and literals of the recorded SQL queries
+	// (see SetQueryMasking).
+	QueryMasking bool
+	// DoubleRaiseWarning warns when raising already raised Errors (see
+	// SetDoubleRaiseWarning).
+	DoubleRaiseWarning bool
+	// SentryContextLines sends that number of source code lines around each frame
+	// to Sentry (see SetSentryContextLines).
+	SentryContextLines int
+	// SentryEvent are the options of the Sentry events built by CaptureSentry (see
+	// SetSentryEventOptions): its Concise and Environment options and its Release
+	// and SampleRate if set, the Scrubbers set with SetSentryEventOptions being
+	// always kept so the scrubbing pipeline can't be disabled by a profile.
+	SentryEvent SentryEventOptions
+}
+
+var (
+	// Development is a profile for local development, with colored reports showing
+	// source code, full stack traces and unmasked messages and queries.
+	Development = Profile{
+		Color:              true,
+		SourceContext:      2,
+		MaxFrames:          0,
+		StackCapture:       true,
+		MessageMasking:     false,
+		QueryMasking:       false,
+		DoubleRaiseWarning: true,
+		SentryContextLines: 5,
+		SentryEvent:        SentryEventOptions{Environment: "development"},
+	}
+	// Production is a profile for production deployments, with plain reports of
+	// bounded stack traces, masked messages and queries and concise Sentry events.
+	Production = Profile{
+		Color:              false,
+		SourceContext:      0,
+		MaxFrames:          50,
+		StackCapture:       true,
+		MessageMasking:     true,
+		QueryMasking:       true,
+		DoubleRaiseWarning: false,
+		SentryContextLines: 0,
+		SentryEvent:        SentryEventOptions{Concise: true, Environment: "production"},
+	}
+)
+
+var _profile = func() *atomic.Pointer[Profile] {
+	profile := &atomic.Pointer[Profile]{}
+	profile.Store(&Profile{Color: true, MessageMasking: true})
+
+	return profile
+}()
+
+// UseProfile applies the profile, such as Development or Production, so services
+// flip the behavior of the errors with a single switch. The profile is also
+// applied at startup if the ERRORS_PROFILE environment variable is set to
+// development or production.
+func UseProfile(profile Profile) {
+	_profile.Store(&profile)
+
+	SetStackCapture(profile.StackCapture)
+	SetMessageMasking(profile.MessageMasking)
+	SetQueryMasking(profile.QueryMasking)
+	SetDoubleRaiseWarning(profile.DoubleRaiseWarning)
+	SetSentryContextLines(profile.SentryContextLines)
+	useSentryProfile(profile.SentryEvent)
+}
+
+func init() {
+	switch strings.ToLower(os.Getenv("ERRORS_PROFILE")) {
+	case "development":
+		UseProfile(Development)
+	case "production":
+		UseProfile(Production)
+	}
+}
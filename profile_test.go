@@ -0,0 +1,95 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+)
+
+// nolint:paralleltest
+func TestUseProfile(t *testing.T) {
+	defer func() {
+		errors.UseProfile(errors.Profile{Color: true, StackCapture: true, MessageMasking: true})
+		errors.SetQueryMasking(true)
+	}()
+
+	errors.UseProfile(errors.Production)
+
+	err := ErrCannotDeposit.Raise().Cause(ErrUserNotFound.Raise("Alex"))
+
+	if report := err.StringReport(); strings.Contains(report, "\x1b") || strings.Contains(report, "> ") {
+		t.FailNow()
+	}
+
+	if body, _ := err.APIBody(1); strings.Contains(string(body), "user Alex not found") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(ErrQuery.Raise().WithQuery("SELECT * FROM users WHERE id = 42").StringReport(), "id = ?") {
+		t.FailNow()
+	}
+
+	errors.UseProfile(errors.Development)
+
+	if report := err.StringReport(); !strings.Contains(report, "\x1b") || !strings.Contains(report, "> ") {
+		t.FailNow()
+	}
+
+	if body, _ := err.APIBody(1); !strings.Contains(string(body), "user Alex not found") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(ErrQuery.Raise().WithQuery("SELECT * FROM users WHERE id = 42").StringReport(), "id = 42") {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestUseProfileSentry(t *testing.T) {
+	defer func() {
+		errors.UseProfile(errors.Profile{Color: true, StackCapture: true, MessageMasking: true})
+		errors.SetQueryMasking(true)
+		errors.SetSentryEventOptions(errors.SentryEventOptions{})
+	}()
+
+	var events []*sentry.Event
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	errors.SetSentryEventOptions(errors.SentryEventOptions{
+		Release: "v1.2.0",
+		Scrubbers: []func(*sentry.Event) *sentry.Event{
+			func(event *sentry.Event) *sentry.Event {
+				delete(event.Extra, "email")
+				return event
+			},
+		},
+	})
+
+	errors.UseProfile(errors.Production)
+
+	ErrUserNotFound.Raise("Alex").Extra(map[string]any{"email": "alex@example.com"}).CaptureSentry(hub)
+
+	if len(events) != 1 || events[0].Environment != "production" || events[0].Release != "v1.2.0" {
+		t.FailNow()
+	}
+
+	if _, ok := events[0].Extra["email"]; ok || events[0].Message != "user Alex not found" {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+var _queryMasking = func() *atomic.Bool {
+	queryMasking := &atomic.Bool{}
+	queryMasking.Store(true)
+
+	return queryMasking
+}()
+
+// SetQueryMasking sets whether to mask the argument values and scrub the literal
+// values of the SQL queries recorded with WithQuery, which usually hold personal
+// or sensitive data (default is enabled).
+func SetQueryMasking(enabled bool) {
+	_queryMasking.Store(enabled)
+}
+
+var (
+	_queryStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	_queryNumericLiteral = regexp.MustCompile(`(^|[^\w$.])(-?\d+(?:\.\d+)?)\b`)
+	_queryTable          = regexp.MustCompile(
+		`(?is)^\s*(select)\b.*?\bfrom\s+([\w."]+)|^\s*(insert)\s+into\s+([\w."]+)|` +
+			`^\s*(update)\s+([\w."]+)|^\s*(delete)\s+from\s+([\w."]+)`)
+)
+
+type query struct {
+	statement string
+	args      []string
+}
+
+// scrubQuery replaces the string and numeric literals of the statement with
+// placeholders.
+func scrubQuery(statement string) string {
+	statement = _queryStringLiteral.ReplaceAllString(statement, "?")
+
+	return _queryNumericLiteral.ReplaceAllString(statement, "${1}?")
+}
+
+// parseQuery returns the operation and table of the statement, if parseable.
+func parseQuery(statement string) (string, string) {
+	match := _queryTable.FindStringSubmatch(statement)
+
+	for i := 1; i+1 < len(match); i += 2 {
+		if match[i] != "" {
+			return strings.ToUpper(match[i]), strings.Trim(match[i+1], `"`)
+		}
+	}
+
+	return "", ""
+}
+
+// WithQuery records the SQL query that failed and its arguments, rendered in
+// their own section of the reports and sent as extra to Sentry, and tags the
+// raised Error with its operation and table (db.operation and db.table) when
+// they can be parsed. The arguments are masked and the literal values of the
+// query scrubbed unless disabled with SetQueryMasking.
+func (self *Error) WithQuery(statement string, args ...any) *Error {
+	masking := _queryMasking.Load()
+
+	recorded := &query{
+		statement: strings.TrimSpace(statement),
+		args:      make([]string, 0, len(args)),
+	}
+
+	if masking {
+		recorded.statement = scrubQuery(recorded.statement)
+	}
+
+	for _, arg := range args {
+		if masking {
+			recorded.args = append(recorded.args, "[REDACTED]")
+		} else {
+			recorded.args = append(recorded.args, fmt.Sprintf("%v", arg))
+		}
+	}
+
+	self.query = recorded
+
+	if operation, table := parseQuery(statement); operation != "" {
+		self.Tags(map[string]any{"db.operation": operation, "db.table": table})
+	}
+
+	return self
+}
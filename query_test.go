@@ -0,0 +1,57 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrQuery = errors.New("cannot query users")
+
+// nolint:paralleltest
+func TestWithQuery(t *testing.T) {
+	err := ErrQuery.Raise().WithQuery(`SELECT * FROM "users" WHERE email = 'alex@example.com' AND age > 30 AND id = $1`, 3107)
+
+	if err.GetTags()["db.operation"] != "SELECT" || err.GetTags()["db.table"] != "users" {
+		t.FailNow()
+	}
+
+	report := err.StringReport()
+	if !strings.Contains(report, `    Query: SELECT * FROM "users" WHERE email = ? AND age > ? AND id = $1`+"\n") {
+		t.FailNow()
+	}
+
+	if !strings.Contains(report, "    Args: [REDACTED]\n") || strings.Contains(report, "3107") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Extra["query"] == nil {
+		t.FailNow()
+	}
+
+	data, _ := err.MarshalBinary()
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || decoded.StringReport() != report {
+		t.FailNow()
+	}
+
+	errors.SetQueryMasking(false)
+	defer errors.SetQueryMasking(true)
+
+	err = ErrQuery.Raise().WithQuery("UPDATE accounts SET balance = 10 WHERE id = ?", 3107)
+	if !strings.Contains(err.StringReport(), "Query: UPDATE accounts SET balance = 10 WHERE id = ?\n    Args: 3107\n") {
+		t.FailNow()
+	}
+
+	if err.GetTags()["db.operation"] != "UPDATE" || err.GetTags()["db.table"] != "accounts" {
+		t.FailNow()
+	}
+
+	if _, ok := ErrQuery.Raise().WithQuery("VACUUM").GetTags()["db.table"]; ok {
+		t.FailNow()
+	}
+}
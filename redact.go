@@ -0,0 +1,276 @@
+package errors
+
+import (
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const _REDACTED = "[REDACTED]"
+
+// ErrRedactionPattern is returned by SetRedaction for invalid key patterns.
+var ErrRedactionPattern = New("invalid redaction pattern %q", false)
+
+// Detector replaces the sensitive parts of a value, such as credit card numbers
+// or emails, with a placeholder.
+type Detector func(value string) string
+
+var (
+	_creditCardPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	_emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// luhn reports whether the digits pass the Luhn checksum of the card numbers.
+func luhn(digits string) bool {
+	sum := 0
+	double := false
+
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// DetectCreditCards is a Detector of the credit card numbers, with or without
+// space or dash separators, that pass the Luhn checksum.
+func DetectCreditCards(value string) string {
+	return _creditCardPattern.ReplaceAllStringFunc(value, func(match string) string {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(match)
+		if !luhn(digits) {
+			return match
+		}
+
+		return _REDACTED
+	})
+}
+
+// DetectEmails is a Detector of the email addresses.
+func DetectEmails(value string) string {
+	return _emailPattern.ReplaceAllString(value, _REDACTED)
+}
+
+// RedactionOptions represents the rules to redact the extra information, tags,
+// breadcrumbs data and attachments of the raised Errors.
+type RedactionOptions struct {
+	// Keys are the patterns of the keys whose values are redacted, matched without
+	// case: exact keys such as "password", globs such as "*_token" or "card_*",
+	// or regular expressions between slashes such as "/^x-.*-secret$/". The
+	// attachments are matched by name.
+	Keys []string
+	// Detectors scrub the string values of the remaining keys and the textual
+	// attachments, such as DetectCreditCards or DetectEmails.
+	Detectors []Detector
+}
+
+type redaction struct {
+	keys      []*regexp.Regexp
+	detectors []Detector
+}
+
+var _redaction atomic.Pointer[redaction]
+
+// keyPattern compiles the pattern of keys into a case-insensitive regular expression.
+func keyPattern(pattern string) (*regexp.Regexp, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		return regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+	}
+
+	glob := regexp.QuoteMeta(pattern)
+	glob = strings.ReplaceAll(glob, `\*`, ".*")
+	glob = strings.ReplaceAll(glob, `\?`, ".")
+
+	return regexp.Compile("(?i)^" + glob + "$")
+}
+
+// SetRedaction sets the rules to redact the extra information, tags, breadcrumbs
+// data and attachments of the Errors when added to them, including the nested
+// maps, so their sensitive values never reach any report format nor sink (default
+// is none). Errors already raised are not affected.
+func SetRedaction(options RedactionOptions) error {
+	if len(options.Keys) == 0 && len(options.Detectors) == 0 {
+		_redaction.Store(nil)
+		return nil
+	}
+
+	rules := &redaction{
+		keys:      make([]*regexp.Regexp, 0, len(options.Keys)),
+		detectors: append([]Detector(nil), options.Detectors...),
+	}
+
+	for _, pattern := range options.Keys {
+		compiled, err := keyPattern(pattern)
+		if err != nil {
+			return ErrRedactionPattern.Raise(pattern).Cause(err)
+		}
+
+		rules.keys = append(rules.keys, compiled)
+	}
+
+	_redaction.Store(rules)
+
+	return nil
+}
+
+func (self *redaction) sensitive(key string) bool {
+	for _, pattern := range self.keys {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (self *redaction) scrub(value string) string {
+	for _, detector := range self.detectors {
+		value = detector(value)
+	}
+
+	return value
+}
+
+func (self *redaction) value(key string, value any) any {
+	if self.sensitive(key) {
+		return _REDACTED
+	}
+
+	switch typed := value.(type) {
+	case string:
+		return self.scrub(typed)
+	case map[string]any:
+		return self.extra(typed)
+	case map[string]string:
+		redacted := make(map[string]string, len(typed))
+		for key, value := range typed {
+			redacted[key] = self.tag(key, value)
+		}
+
+		return redacted
+	default:
+		return value
+	}
+}
+
+func (self *redaction) tag(key string, value string) string {
+	if self.sensitive(key) {
+		return _REDACTED
+	}
+
+	return self.scrub(value)
+}
+
+func (self *redaction) extra(extra map[string]any) map[string]any {
+	redacted := make(map[string]any, len(extra))
+	for key, value := range extra {
+		redacted[key] = self.value(key, value)
+	}
+
+	return redacted
+}
+
+// redactExtra returns the extra redacted as per the rules set with SetRedaction.
+func redactExtra(extra map[string]any) map[string]any {
+	rules := _redaction.Load()
+	if rules == nil || extra == nil {
+		return extra
+	}
+
+	return rules.extra(extra)
+}
+
+// redactTag returns the value of the tag redacted as per the rules set with
+// SetRedaction.
+func redactTag(key string, value string) string {
+	rules := _redaction.Load()
+	if rules == nil {
+		return value
+	}
+
+	return rules.tag(key, value)
+}
+
+// redactQuery returns the raw query string with its values redacted as per the
+// rules set with SetRedaction, keeping the order of the parameters.
+func redactQuery(query string) string {
+	rules := _redaction.Load()
+	if rules == nil || query == "" {
+		return query
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		rawKey, rawValue, found := strings.Cut(pair, "=")
+		if !found {
+			continue
+		}
+
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			value = rawValue
+		}
+
+		if redacted := rules.tag(key, value); redacted != value {
+			pairs[i] = rawKey + "=" + url.QueryEscape(redacted)
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// textual reports whether the content type is of text, such as text/plain,
+// application/json or application/problem+xml, which can be scrubbed.
+func textual(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	if strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+
+	switch mediaType {
+	case "application/json", "application/xml", "application/x-www-form-urlencoded", "application/sql":
+		return true
+	default:
+		return false
+	}
+}
+
+// redactAttachment returns the data of the attachment redacted as per the rules
+// set with SetRedaction: entirely when its name is sensitive, or scrubbed by the
+// detectors when it is textual. Binary data is kept as is.
+func redactAttachment(name string, contentType string, data []byte) []byte {
+	rules := _redaction.Load()
+	if rules == nil {
+		return data
+	}
+
+	if rules.sensitive(name) {
+		return []byte(_REDACTED)
+	}
+
+	if len(rules.detectors) == 0 || !textual(contentType) {
+		return data
+	}
+
+	return []byte(rules.scrub(string(data)))
+}
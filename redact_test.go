@@ -0,0 +1,113 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrPaymentFailed = errors.New("payment failed")
+
+// nolint:paralleltest
+func TestSetRedaction(t *testing.T) {
+	defer func() { _ = errors.SetRedaction(errors.RedactionOptions{}) }()
+
+	err := errors.SetRedaction(errors.RedactionOptions{
+		Keys:      []string{"password", "*_token", "card_*", "/^x-.*-secret$/"},
+		Detectors: []errors.Detector{errors.DetectCreditCards, errors.DetectEmails},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	rerr := ErrPaymentFailed.Raise().Extra(map[string]any{
+		"Password":     "hunter2",
+		"access_token": "tok_live_1",
+		"CARD_NUMBER":  "4111111111111111",
+		"X-Api-Secret": "sec_1",
+		"note":         "card 4111 1111 1111 1111 of alex@example.com",
+		"order":        "1234567890123",
+		"nested":       map[string]any{"refresh_token": "tok_live_2", "attempt": 3},
+	}).Tags(map[string]any{"session_token": "tok_live_3", "region": "eu"}).
+		Breadcrumb("charged", map[string]any{"card_holder": "Alex"})
+
+	extras := rerr.Extras()
+	if extras["Password"] != "[REDACTED]" || extras["access_token"] != "[REDACTED]" ||
+		extras["CARD_NUMBER"] != "[REDACTED]" || extras["X-Api-Secret"] != "[REDACTED]" ||
+		extras["note"] != "card [REDACTED] of [REDACTED]" || extras["order"] != "1234567890123" {
+		t.FailNow()
+	}
+
+	if nested, ok := extras["nested"].(map[string]any); !ok || nested["refresh_token"] != "[REDACTED]" ||
+		nested["attempt"] != 3 {
+		t.FailNow()
+	}
+
+	if tags := rerr.GetTags(); tags["session_token"] != "[REDACTED]" || tags["region"] != "eu" {
+		t.FailNow()
+	}
+
+	encoded, _ := json.Marshal(rerr)
+	outputs := []string{
+		rerr.Report(errors.ReportOptions{All: true}),
+		string(encoded),
+		fmt.Sprintf("%v", rerr.SentryReport().Extra),
+		fmt.Sprintf("%v", rerr.SentryReport().Breadcrumbs[0].Data),
+	}
+
+	for _, output := range outputs {
+		if strings.Contains(output, "hunter2") || strings.Contains(output, "tok_live") ||
+			strings.Contains(output, "sec_1") || strings.Contains(output, "alex@example.com") ||
+			strings.Contains(output, "4111") || strings.Contains(output, "Alex") {
+			t.FailNow()
+		}
+	}
+
+	rerr = ErrPaymentFailed.Raise().
+		Attach("charge.json", "application/json; charset=utf-8", []byte(`{"email":"alex@example.com","amount":10}`)).
+		Attach("session_token", "text/plain", []byte("tok_live_4")).
+		Attach("receipt.pdf", "application/pdf", []byte("alex@example.com"))
+
+	var decoded struct {
+		Attachments []struct {
+			Data []byte `json:"data"`
+		} `json:"attachments"`
+	}
+
+	encoded, _ = json.Marshal(errors.Detailed(rerr))
+	if json.Unmarshal(encoded, &decoded) != nil || len(decoded.Attachments) != 3 {
+		t.FailNow()
+	}
+
+	if string(decoded.Attachments[0].Data) != `{"email":"[REDACTED]","amount":10}` ||
+		string(decoded.Attachments[1].Data) != "[REDACTED]" || string(decoded.Attachments[2].Data) != "alex@example.com" {
+		t.FailNow()
+	}
+
+	if err := errors.SetRedaction(errors.RedactionOptions{Keys: []string{"/(/"}}); !errors.ErrRedactionPattern.Is(err) {
+		t.FailNow()
+	}
+
+	_ = errors.SetRedaction(errors.RedactionOptions{})
+
+	if ErrPaymentFailed.Raise().Extra(map[string]any{"password": "hunter2"}).Extras()["password"] != "hunter2" {
+		t.FailNow()
+	}
+}
+
+func TestDetectors(t *testing.T) {
+	t.Parallel()
+
+	if errors.DetectCreditCards("4242-4242-4242-4242 and 4242-4242-4242-4241") != "[REDACTED] and 4242-4242-4242-4241" {
+		t.FailNow()
+	}
+
+	if errors.DetectEmails("from alex.doe+test@mail.example.com.") != "from [REDACTED]." {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,579 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	_TRUNCATED_MARKER = "[... report truncated]\n"
+	_TRACEBACK        = "Traceback (most recent call last):\n"
+	_CAUSED_BY        = "\nCaused by the following error:\n"
+)
+
+var _traceLink atomic.Pointer[string]
+
+// SetTraceLink sets the format (with a %s verb for the trace ID) of the link
+// rendered in string reports for Errors tagged with a trace_id, such as
+// "https://grafana.example.com/explore?traceId=%s" (default is the trace ID).
+func SetTraceLink(format string) {
+	_traceLink.Store(&format)
+}
+
+func traceLink(traceID string) string {
+	format := _traceLink.Load()
+	if format == nil || *format == "" {
+		return traceID
+	}
+
+	return fmt.Sprintf(*format, traceID)
+}
+
+// ReportOptions represents the options to render a string report of an Error.
+type ReportOptions struct {
+	// All renders all errors wrapped within the Error instead of only the first.
+	All bool
+	// Color renders the messages with ANSI colors.
+	Color bool
+	// MaxFrames limits the number of rendered frames of each stack trace to the
+	// most recent calls (0 means unlimited).
+	MaxFrames int
+	// SourceContext renders that number of source code lines around each frame
+	// when the source files are available (0 means none).
+	SourceContext int
+	// Compact collapses the report into a single line with escaped newlines.
+	Compact bool
+	// Redact hides the values of the extra information, which may hold
+	// personal or sensitive data.
+	Redact bool
+	// Width wraps the lines longer than that number of columns, preserving their
+	// indentation (0 means no wrapping).
+	Width int
+	// RuntimeFrames renders the runtime bootstrap frames below main.main and the
+	// start of goroutines (runtime.main, runtime.goexit), which are omitted by
+	// default as noise. They are always kept in the StackTrace.
+	RuntimeFrames bool
+}
+
+func stringSourceContext(frame Frame, context int) string {
+	lines := sourceLines(frame.File)
+	if frame.Line < 1 || frame.Line > len(lines) {
+		return ""
+	}
+
+	first := max(frame.Line-context, 1)
+	last := min(frame.Line+context, len(lines))
+	width := len(strconv.Itoa(last))
+
+	report := ""
+	for line := first; line <= last; line++ {
+		marker := "  "
+		if line == frame.Line {
+			marker = "> "
+		}
+
+		report += "        " + marker + fmt.Sprintf("%*d", width, line) + " | " + lines[line-1] + "\n"
+	}
+
+	return report
+}
+
+func stringStackTrace(stackTrace []Frame, options ReportOptions, seenTraces map[string]bool) string {
+	if len(stackTrace) == 0 {
+		return "    (Stack trace not available)\n"
+	}
+
+	if !options.RuntimeFrames {
+		stackTrace = trimRuntimeFrames(stackTrace)
+	}
+
+	report := ""
+	ellipsis := false
+	aggregation := _frameAggregation.Load()
+	formatter := *_frameFormatter.Load()
+	omitted := 0
+
+	last := len(stackTrace) - 1
+	if options.MaxFrames > 0 && options.MaxFrames <= last {
+		last = options.MaxFrames - 1
+		report += "    [... " + strconv.Itoa(len(stackTrace)-options.MaxFrames) + " frames omitted]\n"
+	}
+
+	for i := last; i >= 0; i-- {
+		fileline := stackTrace[i].File + ":" + strconv.Itoa(stackTrace[i].Line)
+
+		_, seen := seenTraces[fileline]
+		if !seen {
+			if omitted > 0 {
+				report += "    " + omittedFrames(omitted) + "\n"
+				omitted = 0
+			}
+
+			color := _theme.Load().Frame
+			if stackTrace[i].Class != FrameInApp {
+				color = _COLOR_DIM
+			}
+
+			seenTraces[fileline] = true
+			report += "    " + colorize(strings.ReplaceAll(formatter(stackTrace[i]), "\n", "\n    "),
+				color, options.Color) + "\n"
+
+			if options.SourceContext > 0 {
+				report += stringSourceContext(stackTrace[i], options.SourceContext)
+			}
+		} else if aggregation {
+			omitted++
+		} else if !ellipsis {
+			ellipsis = true
+			report += "    [...]\n"
+		}
+	}
+
+	if omitted > 0 {
+		report += "    " + omittedFrames(omitted) + "\n"
+	}
+
+	return report
+}
+
+// sortedKeys returns the keys of the map sorted, so the reports are deterministic.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (self Error) stringReport(options ReportOptions, seenTraces map[string]bool, depth int) string {
+	report := stringStackTrace(self.stackTrace, options, seenTraces)
+	report += colorize(self.formatted(), _theme.Load().Message, options.Color) + "\n"
+
+	if len(self.extra) > 0 {
+		extra := ""
+		for _, key := range sortedKeys(self.extra) {
+			if options.Redact {
+				extra += key + "=[REDACTED] "
+			} else {
+				extra += key + "=" + fmt.Sprintf("%v", self.extra[key]) + " "
+			}
+		}
+		report += "    " + colorize(extra, _theme.Load().Extra, options.Color) + "\n"
+	}
+
+	if missing := self.flaggedExtras(); missing != "" {
+		report += "    " + colorize("Missing extras: "+missing, _theme.Load().Message, options.Color) + "\n"
+	}
+
+	if self.payload != nil {
+		payload := "[REDACTED]"
+		if !options.Redact {
+			payload = fmt.Sprintf("%+v", self.payload)
+		}
+		report += "    " + colorize("payload="+payload, _theme.Load().Extra, options.Color) + "\n"
+	}
+
+	if self.query != nil {
+		report += "    Query: " + strings.ReplaceAll(self.query.statement, "\n", "\n           ") + "\n"
+		if len(self.query.args) > 0 {
+			report += "    Args: " + strings.Join(self.query.args, ", ") + "\n"
+		}
+	}
+
+	if details := self.stringDetails(); len(details) > 0 {
+		report += "    Details:\n"
+		for _, detail := range details {
+			report += "      " + detail + "\n"
+		}
+	}
+
+	if self.docs != "" {
+		report += "    See: " + self.docs + "\n"
+	}
+
+	if traceID, ok := self.tags["trace_id"]; ok {
+		report += "    Trace: " + traceLink(traceID) + "\n"
+	}
+
+	if self.sentryEventID != "" {
+		report += "    Sentry: " + self.sentryEventID + "\n"
+	}
+
+	if self.memStats != nil {
+		report += "    Memory: " + self.memStats.String() + "\n"
+	}
+
+	if !self.raisedAt.IsZero() {
+		report += "    Raised at: " + self.raisedAt.Format(time.RFC3339Nano) + "\n"
+	}
+
+	if len(self.breadcrumbs) > 0 {
+		report += "    Breadcrumbs:\n"
+		for _, breadcrumb := range self.breadcrumbs {
+			report += "      " + breadcrumb.timestamp.Format(time.RFC3339Nano) + " " + breadcrumb.message
+			for _, key := range sortedKeys(breadcrumb.data) {
+				if options.Redact {
+					report += " " + key + "=[REDACTED]"
+				} else {
+					report += " " + key + "=" + fmt.Sprintf("%v", breadcrumb.data[key])
+				}
+			}
+			report += "\n"
+		}
+	}
+
+	if len(self.observedAt) > 0 {
+		report += "\nObserved here (most recent call last):\n"
+		report += stringStackTrace(self.observedAt, options, seenTraces)
+	}
+
+	if options.All && self.cause != nil && depth >= maxChainDepth() {
+		report += "\n" + truncatedChain(depth) + "\n"
+	} else if options.All && self.cause != nil {
+		report += _CAUSED_BY
+		switch cause := self.cause.(type) {
+		case Error:
+			report += cause.stringReport(options, seenTraces, depth+1)
+		case *Error:
+			report += cause.stringReport(options, seenTraces, depth+1)
+		default:
+			report += stringStackTrace(foreignStackTrace(cause), options, seenTraces)
+			report += colorize(cause.Error(), _theme.Load().Message, options.Color) + " (" +
+				strings.TrimPrefix(reflect.TypeOf(cause).String(), "*") + ")"
+			if pkg := foreignPackage(cause); pkg != "" {
+				report += " from package " + pkg
+			}
+			report += "\n"
+		}
+	}
+
+	return report
+}
+
+// Report returns a string containing the information about the first error or
+// all errors wrapped within the Error itself (including the messages, stack
+// traces, extra...) rendered according to the options.
+func (self Error) Report(options ReportOptions) string {
+	seenTraces := make(map[string]bool)
+
+	report := colorize(self.String(), _theme.Load().Headline, options.Color) + "\n\n"
+	if opPath := self.OpPath(); opPath != "" {
+		report += "Operation: " + opPath + "\n\n"
+	}
+
+	report += _TRACEBACK
+	report += self.stringReport(options, seenTraces, 1)
+
+	if options.Width > 0 {
+		report = wrap(report, options.Width)
+	}
+
+	if options.Compact {
+		report = strings.ReplaceAll(strings.TrimRight(report, "\n"), "\n", "\\n")
+	}
+
+	return report
+}
+
+// visibleWidth returns the number of columns of the text, ANSI escape sequences
+// not counting.
+func visibleWidth(text string) int {
+	width := 0
+
+	for i := 0; i < len(text); {
+		if text[i] == '\x1b' {
+			if end := strings.IndexByte(text[i:], 'm'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		width++
+		i += size
+	}
+
+	return width
+}
+
+// wrap wraps the lines of the text longer than the width, continuing them with
+// their same indentation. Lines are broken after spaces, separators of file paths
+// or commas when possible, so messages, extra pairs and paths stay readable, and
+// anywhere otherwise. ANSI escape sequences don't count as columns.
+func wrap(text string, width int) string {
+	var wrapped strings.Builder
+
+	for i, line := range strings.Split(text, "\n") {
+		if i > 0 {
+			wrapped.WriteByte('\n')
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		if len(indent) >= width/2 {
+			indent = ""
+		}
+
+		current := ""
+		column := 0
+		breakAt := -1
+
+		for j := 0; j < len(line); {
+			if line[j] == '\x1b' {
+				if end := strings.IndexByte(line[j:], 'm'); end >= 0 {
+					current += line[j : j+end+1]
+					j += end + 1
+
+					continue
+				}
+			}
+
+			_, size := utf8.DecodeRuneInString(line[j:])
+
+			if column >= width {
+				tail := ""
+				if breakAt > len(indent) {
+					current, tail = current[:breakAt], strings.TrimLeft(current[breakAt:], " ")
+				}
+
+				wrapped.WriteString(strings.TrimRight(current, " ") + "\n")
+				current = indent + tail
+				column = len(indent) + visibleWidth(tail)
+				breakAt = -1
+			}
+
+			current += line[j : j+size]
+			column++
+
+			if line[j] == ' ' || line[j] == '/' || line[j] == ',' {
+				breakAt = len(current)
+			}
+
+			j += size
+		}
+
+		wrapped.WriteString(current)
+	}
+
+	return wrapped.String()
+}
+
+// ReportTo writes the report of all errors wrapped within the Error to the
+// writer. When the writer is a terminal, the report is colored (unless NO_COLOR
+// is set) and wrapped to the terminal's width (or the width set in COLUMNS when
+// it can't be queried), otherwise it is plain text.
+func (self Error) ReportTo(writer io.Writer) (int, error) {
+	profile := _profile.Load()
+	options := ReportOptions{All: true, SourceContext: profile.SourceContext, MaxFrames: profile.MaxFrames}
+
+	if file, ok := writer.(*os.File); ok {
+		if width, terminal := terminalWidth(file); terminal {
+			options.Color = os.Getenv("NO_COLOR") == ""
+			options.Width = width
+
+			if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width == 0 {
+				options.Width = columns
+			}
+		}
+	}
+
+	return io.WriteString(writer, self.Report(options))
+}
+
+// StringReport returns a string containing all the information about the first
+// error (including the message, stack trace, extra...) or about all errors
+// wrapped within the Error itself (default is all), rendered as per the profile
+// set with UseProfile (default is colored).
+func (self Error) StringReport(all ...bool) string {
+	_all := true
+	if len(all) > 0 {
+		_all = all[0]
+	}
+
+	profile := _profile.Load()
+
+	return self.Report(ReportOptions{
+		All:           _all,
+		Color:         profile.Color,
+		SourceContext: profile.SourceContext,
+		MaxFrames:     profile.MaxFrames,
+	})
+}
+
+// StringReportN returns the same information as StringReport (all errors) but
+// without colors and bounded to a number of bytes, for log pipelines with strict
+// per-line limits such as 16KB syslog. Reports that don't fit keep only the most
+// recent frames of their stack traces and then each error is cut at a line
+// boundary with a marker to its share of the bytes, so the headline and the
+// innermost error, the root cause, are always kept and lines are never torn apart.
+func (self Error) StringReportN(maxBytes int) string {
+	report := ""
+	for _, frames := range []int{0, 8, 4, 2, 1} {
+		report = self.Report(ReportOptions{All: true, MaxFrames: frames})
+		if len(report) <= maxBytes {
+			return report
+		}
+	}
+
+	if truncated, ok := truncateErrors(report, maxBytes); ok {
+		return truncated
+	}
+
+	budget := maxBytes - len(_TRUNCATED_MARKER)
+	if budget <= 0 {
+		return _TRUNCATED_MARKER[:max(maxBytes, 0)]
+	}
+
+	truncated := truncateLines(report, maxBytes)
+
+	// The headline alone doesn't fit, so it is cut without splitting characters
+	if truncated == "" {
+		truncated = strings.ToValidUTF8(report[:budget-1], "") + "\n" + _TRUNCATED_MARKER
+	}
+
+	return truncated
+}
+
+// truncateErrors bounds the report to a number of bytes keeping its headline and
+// innermost error, and sharing the remaining bytes between the outer errors,
+// which are dropped when not even their messages fit.
+func truncateErrors(report string, maxBytes int) (string, bool) {
+	head, traceback, ok := strings.Cut(report, _TRACEBACK)
+	if !ok {
+		return "", false
+	}
+
+	head += _TRACEBACK
+	sections := strings.Split(traceback, _CAUSED_BY)
+	outer, innermost := sections[:len(sections)-1], sections[len(sections)-1]
+
+	budget := maxBytes - len(head)
+	if len(outer) > 0 {
+		budget -= len(_TRUNCATED_MARKER) + len(_CAUSED_BY)
+	}
+
+	innermost = truncateSection(innermost, budget)
+	if innermost == "" {
+		return "", false
+	}
+
+	budget -= len(innermost)
+
+	if len(outer) == 0 {
+		return head + innermost, true
+	}
+
+	lengths := make([]int, len(outer))
+	for i, section := range outer {
+		lengths[i] = len(section)
+	}
+
+	truncated := head
+	dropped := false
+
+	shares := fairShares(lengths, budget-(len(outer)-1)*len(_CAUSED_BY))
+	for i, section := range outer {
+		if i > 0 && !dropped {
+			truncated += _CAUSED_BY
+		}
+
+		if section = truncateSection(section, shares[i]); section != "" {
+			truncated += section
+			dropped = false
+		} else if !dropped {
+			truncated += _TRUNCATED_MARKER
+			dropped = true
+		}
+	}
+
+	if dropped {
+		truncated += innermost
+	} else {
+		truncated += _CAUSED_BY + innermost
+	}
+
+	// The markers of the dropped errors didn't fit, so only the innermost is kept
+	if len(truncated) > maxBytes {
+		truncated = head + _TRUNCATED_MARKER + innermost
+	}
+
+	return truncated, true
+}
+
+// truncateSection cuts the report of an error so it fits in the number of bytes,
+// dropping its stack trace first so its message and extra are kept the longest.
+func truncateSection(section string, maxBytes int) string {
+	if len(section) <= maxBytes {
+		return section
+	}
+
+	lines := strings.SplitAfter(section, "\n")
+	for i, line := range lines {
+		if line != "" && line[0] != ' ' {
+			section = strings.Join(lines[i:], "")
+			break
+		}
+	}
+
+	if len(section)+len(_TRUNCATED_MARKER) <= maxBytes {
+		return section + _TRUNCATED_MARKER
+	}
+
+	return truncateLines(section, maxBytes)
+}
+
+// truncateLines cuts the text at a line boundary with a marker so it fits in the
+// number of bytes, returning nothing when not even its first line fits.
+func truncateLines(text string, maxBytes int) string {
+	truncated := ""
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if len(truncated)+len(line)+len(_TRUNCATED_MARKER) > maxBytes {
+			break
+		}
+
+		truncated += line
+	}
+
+	if truncated == "" {
+		return ""
+	}
+
+	return truncated + _TRUNCATED_MARKER
+}
+
+// fairShares splits the budget between the lengths so the ones shorter than an
+// even share are fully kept and their leftovers go to the longer ones.
+func fairShares(lengths []int, budget int) []int {
+	order := make([]int, len(lengths))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return lengths[order[i]] < lengths[order[j]] })
+
+	shares := make([]int, len(lengths))
+	for k, i := range order {
+		share := max(budget, 0) / (len(order) - k)
+		shares[i] = min(lengths[i], share)
+		budget -= shares[i]
+	}
+
+	return shares
+}
+
+// CompactReport returns the same information as StringReport (all errors) but
+// without colors and collapsed into a single line with escaped newlines, so log
+// aggregators such as Loki don't split the traceback into several entries.
+func (self Error) CompactReport() string {
+	return self.Report(ReportOptions{All: true, Compact: true})
+}
@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const _MAX_REQUEST_BODY_SIZE = 64 << 10
+
+// httpRequest represents the snapshot of the HTTP request recorded with WithRequest.
+type httpRequest struct {
+	URL         string
+	Method      string
+	Data        string
+	QueryString string
+	Headers     map[string]string
+	Env         map[string]string
+}
+
+var _sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// WithRequest records a snapshot of the HTTP request being handled when the
+// Error was raised (method, URL, headers and remote address) into the extra,
+// which is also sent as the request of the Sentry reports, and optionally a copy
+// of its body, truncated to 64KiB (default is false). The body remains readable
+// by the handler. Authorization and cookie headers are redacted, the credentials
+// of the URL are dropped, and the headers, query values and body also go through
+// the rules set with SetRedaction. A nil request records nothing.
+func (self *Error) WithRequest(request *http.Request, body ...bool) *Error {
+	if request == nil {
+		return self
+	}
+
+	_body := false
+	if len(body) > 0 {
+		_body = body[0]
+	}
+
+	headers := make(map[string]string, len(request.Header))
+	for key, values := range request.Header {
+		if _sensitiveHeaders[http.CanonicalHeaderKey(key)] {
+			headers[key] = "[REDACTED]"
+		} else {
+			headers[key] = redactTag(key, strings.Join(values, ", "))
+		}
+	}
+
+	if request.Host != "" {
+		headers["Host"] = request.Host
+	}
+
+	// The credentials of the URL are never recorded
+	received := url.URL{}
+	if request.URL != nil {
+		received = *request.URL
+		received.User = nil
+	}
+
+	query := redactQuery(received.RawQuery)
+
+	location := received
+	location.RawQuery = ""
+
+	if location.Host == "" {
+		location.Host = request.Host
+	}
+
+	if location.Scheme == "" {
+		location.Scheme = "http"
+		if request.TLS != nil {
+			location.Scheme = "https"
+		}
+	}
+
+	self.request = &httpRequest{
+		URL:         location.String(),
+		Method:      request.Method,
+		QueryString: query,
+		Headers:     headers,
+		Env:         map[string]string{"REMOTE_ADDR": request.RemoteAddr},
+	}
+
+	extra := map[string]any{
+		"request.method":      request.Method,
+		"request.url":         requestURL(received, query),
+		"request.headers":     headers,
+		"request.remote_addr": request.RemoteAddr,
+	}
+
+	if _body && request.Body != nil && request.Body != http.NoBody {
+		data, err := io.ReadAll(io.LimitReader(request.Body, _MAX_REQUEST_BODY_SIZE))
+		request.Body = readCloser{io.MultiReader(bytes.NewReader(data), request.Body), request.Body}
+
+		if err == nil {
+			self.request.Data = redactTag("request.body", string(data))
+			extra["request.body"] = string(data)
+		}
+	}
+
+	return self.Extra(extra)
+}
+
+// requestURL returns the URL as received, with the query string replaced.
+func requestURL(received url.URL, query string) string {
+	received.RawQuery = query
+
+	return received.String()
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
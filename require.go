@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var _requireExtras = func() *atomic.Bool {
+	requireExtras := &atomic.Bool{}
+	enabled, _ := strconv.ParseBool(os.Getenv("ERRORS_REQUIRE_EXTRAS"))
+	requireExtras.Store(enabled)
+
+	return requireExtras
+}()
+
+// SetRequireExtras enables or disables flagging the Errors reported without the
+// extra keys required by their templates with Require, in their string reports
+// and as a missing_extras tag of their Sentry events, such as in development or
+// CI (default is disabled, unless the ERRORS_REQUIRE_EXTRAS environment variable
+// is set to true).
+func SetRequireExtras(enabled bool) {
+	_requireExtras.Store(enabled)
+}
+
+// Require returns a copy of the template requiring its Errors to be raised with
+// the extra keys, such as ("userID", "accountID"), flagged when they reach
+// reporting without them if enabled with SetRequireExtras.
+func (self Template) Require(keys ...string) Template {
+	template := *self.base()
+	template.required = append(slices.Clone(template.required), keys...)
+
+	self.template = &template
+	_registry.Store(kindKey(&template), self)
+
+	return self
+}
+
+// MissingExtras returns the extra keys required by the Error's template with
+// Require that are missing from the Error and the errors wrapped within itself.
+func (self Error) MissingExtras() []string {
+	if len(self.required) == 0 {
+		return nil
+	}
+
+	extras := self.AllExtras()
+
+	var missing []string
+	for _, key := range self.required {
+		if _, ok := extras[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// flaggedExtras returns the missing required extra keys of the Error joined, if
+// the flagging is enabled.
+func (self Error) flaggedExtras() string {
+	if !_requireExtras.Load() {
+		return ""
+	}
+
+	return strings.Join(self.MissingExtras(), ", ")
+}
@@ -1,3 +1,4 @@
+import glob
 import os
 import re
 
@@ -7,16 +8,27 @@ from .envs import Envs
 from .tools import Tools
 
 
+def nested_modules():
+    """Directories of the nested modules, which are not matched by ./... from the root module."""
+
+    return sorted(os.path.dirname(path) for path in glob.glob("*/go.mod"))
+
+
 @task(
     help={
         "test": "[<PACKAGE_PATH>]::[<TEST_NAME>]. If empty, it will run all tests.",
         "verbose": "Show stdout of tests.",
         "show": "Show coverprofile page.",
+        "headless": "Run tests in headless mode (errors_headless build tag).",
+        "modules": "Run tests of every nested module instead of the root module.",
     },
 )
-def test(context, test="", verbose=False, show=False):
+def test(context, test="", verbose=False, show=False, headless=False, modules=False):
     """Run tests."""
 
+    if show and modules:
+        context.fail("show command only available for the root module!")
+
     test_arg = "./..."
     if test:
         test = test.split("::")
@@ -37,15 +49,22 @@ def test(context, test="", verbose=False, show=False):
     if show:
         coverprofile_arg = "-coverprofile=coverage.out"
 
-    result = context.run(
-        f"{Tools.Test} --format=testname --no-color=False -- {verbose_arg} {parallel_arg} -race -count=1 -cover {coverprofile_arg} {test_arg}",
-    )
+    tags_arg = ""
+    if headless:
+        tags_arg = "-tags errors_headless"
+
+    command = f"{Tools.Test} --format=testname --no-color=False -- {verbose_arg} {parallel_arg} {tags_arg} -race -count=1 -cover {coverprofile_arg} {test_arg}"
+
+    stdout = ""
+    for module in nested_modules() if modules else ["."]:
+        with context.cd(module):
+            stdout += context.run(command).stdout
 
-    if "DONE 0 tests" not in result.stdout:
+    if "DONE 0 tests" not in stdout:
         packages = 0
         coverage = 0.0
 
-        for cover in re.findall(r"[0-9]+\.[0-9]+(?=%)", result.stdout):
+        for cover in re.findall(r"[0-9]+\.[0-9]+(?=%)", stdout):
             packages += 1
             coverage += float(cover)
 
@@ -64,11 +83,17 @@ def test(context, test="", verbose=False, show=False):
         context.remove("coverage.out")
 
 
-@task()
-def lint(context):
+@task(
+    help={
+        "modules": "Run linter on every nested module instead of the root module.",
+    },
+)
+def lint(context, modules=False):
     """Run linter."""
 
-    context.run(f"{Tools.Lint} run ./... -c .golangci.yaml")
+    for module in nested_modules() if modules else ["."]:
+        with context.cd(module):
+            context.run(f"{Tools.Lint} run ./... -c {os.path.relpath('.golangci.yaml', module)}")
 
 
 @task()
@@ -0,0 +1,456 @@
+//go:build !tinygo && !errors_headless
+
+package errors
+
+import (
+	goerrors "errors"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/getsentry/sentry-go"
+)
+
+var _sentrySampling = struct {
+	sync.RWMutex
+	kinds   map[string]float64
+	modules map[string]float64
+}{
+	kinds:   make(map[string]float64),
+	modules: make(map[string]float64),
+}
+
+// SetSentrySampleRate sets the rate (from 0.0 to 1.0) at which Errors of the
+// template's type are reported to Sentry by CaptureSentry (default is 1.0).
+func SetSentrySampleRate(template Template, rate float64) {
+	_sentrySampling.Lock()
+	defer _sentrySampling.Unlock()
+
+	_sentrySampling.kinds[kindKey(template.base())] = rate
+}
+
+// SetSentryModuleSampleRate sets the rate (from 0.0 to 1.0) at which Errors
+// declared in a package are reported to Sentry by CaptureSentry (default is 1.0).
+func SetSentryModuleSampleRate(module string, rate float64) {
+	_sentrySampling.Lock()
+	defer _sentrySampling.Unlock()
+
+	_sentrySampling.modules[module] = rate
+}
+
+// IgnoreKinds stops Errors of the templates' types from being reported to
+// Sentry by CaptureSentry, such as expected business errors.
+func IgnoreKinds(templates ...Template) {
+	for _, template := range templates {
+		SetSentrySampleRate(template, 0.0)
+	}
+}
+
+// IgnoreModules stops Errors declared in the packages from being reported to
+// Sentry by CaptureSentry.
+func IgnoreModules(modules ...string) {
+	for _, module := range modules {
+		SetSentryModuleSampleRate(module, 0.0)
+	}
+}
+
+func sentrySampled(err Error) bool {
+	_sentrySampling.RLock()
+	defer _sentrySampling.RUnlock()
+
+	rate, ok := _sentrySampling.kinds[kindKey(&err)]
+	if !ok {
+		rate, ok = _sentrySampling.modules[err.module]
+	}
+
+	if !ok || rate >= 1.0 {
+		return true
+	}
+
+	// nolint:gosec
+	return rand.Float64() < rate
+}
+
+var _sentryContextLines atomic.Int64
+
+// SetSentryContextLines sets the number of source lines around each frame sent to
+// Sentry as its code context, read from the source files when they are available
+// where the binary runs, such as in development or when shipped along (default is
+// 0, disabled).
+func SetSentryContextLines(lines int) {
+	_sentryContextLines.Store(int64(max(lines, 0)))
+}
+
+// sentryContextLines fills the code context of the Sentry frame, if enabled and
+// its source file is available.
+func sentryContextLines(frame *sentry.Frame) {
+	context := int(_sentryContextLines.Load())
+	if context == 0 || frame.AbsPath == "" || frame.Lineno <= 0 {
+		return
+	}
+
+	lines := sourceLines(frame.AbsPath)
+	if frame.Lineno > len(lines) {
+		return
+	}
+
+	line := frame.Lineno - 1
+
+	frame.PreContext = append([]string(nil), lines[max(line-context, 0):line]...)
+	frame.ContextLine = lines[line]
+	frame.PostContext = append([]string(nil), lines[line+1:min(line+1+context, len(lines))]...)
+}
+
+// SentryEventOptions represents the options to build the Sentry Event of an Error.
+type SentryEventOptions struct {
+	// Concise sets the message of the event to the Error's message, moving the
+	// full string report into the extra.
+	Concise bool
+	// Environment is the environment of the event, such as production.
+	Environment string
+	// Release is the release of the event, such as the version of the service.
+	Release string
+	// SampleRate is the rate (from 0.0 to 1.0) at which events are built, no event
+	// being built otherwise (0 means always).
+	SampleRate float64
+	// Scrubbers modify the built event in order, such as to remove personal data,
+	// or drop it by returning nil.
+	Scrubbers []func(event *sentry.Event) *sentry.Event
+}
+
+var _sentryEventOptions atomic.Pointer[SentryEventOptions]
+
+// SetSentryEventOptions sets the options of the Sentry Events built by
+// CaptureSentry and SentrySink, so a single scrubbing pipeline is enforced
+// across services instead of post-processing the events in each of them.
+func SetSentryEventOptions(options SentryEventOptions) {
+	_sentryEventOptions.Store(&options)
+}
+
+// useSentryProfile applies the options of a profile to the Sentry Events: its
+// Concise and Environment options and its Release and SampleRate if set, always
+// keeping the Scrubbers set with SetSentryEventOptions.
+func useSentryProfile(profile SentryEventOptions) {
+	options := SentryEventOptions{}
+	if current := _sentryEventOptions.Load(); current != nil {
+		options = *current
+	}
+
+	options.Concise = profile.Concise
+	options.Environment = profile.Environment
+
+	if profile.Release != "" {
+		options.Release = profile.Release
+	}
+
+	if profile.SampleRate != 0 {
+		options.SampleRate = profile.SampleRate
+	}
+
+	_sentryEventOptions.Store(&options)
+}
+
+// BuildSentryEvent returns a Sentry Event containing all the information about
+// the first error and all errors wrapped within itself (including the types,
+// packages, messages, stack traces, extra, tags...) built with optional options,
+// without touching any Sentry hub or scope, or nil if sampled out or dropped by a
+// scrubber.
+func (self Error) BuildSentryEvent(options ...SentryEventOptions) *sentry.Event {
+	_options := SentryEventOptions{}
+	if len(options) > 0 {
+		_options = options[0]
+	}
+
+	// nolint:gosec
+	if _options.SampleRate > 0 && _options.SampleRate < 1.0 && rand.Float64() >= _options.SampleRate {
+		return nil
+	}
+
+	report := sentry.NewEvent()
+	report.Level = self.level.sentryLevel()
+	report.Tags["package"] = self.module
+
+	if opPath := self.OpPath(); opPath != "" {
+		report.Tags["operation"] = opPath
+	}
+
+	if self.docs != "" {
+		report.Tags["docs"] = self.docs
+	}
+
+	if missing := self.flaggedExtras(); missing != "" {
+		report.Tags["missing_extras"] = missing
+	}
+
+	if _options.Concise {
+		report.Message = self.formatted()
+		report.Extra["report"] = self.Report(ReportOptions{All: true})
+	} else {
+		report.Message = self.Report(ReportOptions{All: true})
+	}
+
+	self.sentryReport(report, make(map[string]bool), 1)
+
+	sort.SliceStable(report.Breadcrumbs, func(i, j int) bool {
+		return report.Breadcrumbs[i].Timestamp.Before(report.Breadcrumbs[j].Timestamp)
+	})
+
+	if _options.Environment != "" {
+		report.Environment = _options.Environment
+	}
+
+	if _options.Release != "" {
+		report.Release = _options.Release
+	}
+
+	for _, scrubber := range _options.Scrubbers {
+		if report = scrubber(report); report == nil {
+			return nil
+		}
+	}
+
+	return report
+}
+
+// CaptureSentry reports the Error to Sentry through the hub (default is the
+// current hub), built with the options set with SetSentryEventOptions, unless its
+// type or package is ignored, sampled out, over its report limit or dropped by a
+// scrubber, returning the ID of the reported event if any, which is also kept in
+// the Error to be shown in its reports and responses.
+func (self *Error) CaptureSentry(hub ...*sentry.Hub) *sentry.EventID {
+	_hub := sentry.CurrentHub()
+	if len(hub) > 0 {
+		_hub = hub[0]
+	}
+
+	self.escalate()
+
+	if !sentrySampled(*self) {
+		return nil
+	}
+
+	if !self.allowReport() {
+		return nil
+	}
+
+	options := SentryEventOptions{}
+	if defaults := _sentryEventOptions.Load(); defaults != nil {
+		options = *defaults
+	}
+
+	report := self.BuildSentryEvent(options)
+	if report == nil {
+		return nil
+	}
+
+	if self.suppressed > 0 {
+		report.Extra["suppressed"] = self.suppressed
+	}
+
+	eventID := _hub.CaptureEvent(report)
+	if eventID != nil {
+		self.sentryEventID = string(*eventID)
+	}
+
+	return eventID
+}
+
+// SentryEventID returns the ID of the Sentry event the Error was reported as by
+// CaptureSentry, if any.
+func (self Error) SentryEventID() string {
+	return self.sentryEventID
+}
+
+func (self Level) sentryLevel() sentry.Level {
+	switch self {
+	case LevelWarning:
+		return sentry.LevelWarning
+	case LevelFatal:
+		return sentry.LevelFatal
+	default:
+		return sentry.LevelError
+	}
+}
+
+func sentryStackTrace(stackTrace []Frame, seenTraces map[string]bool) *sentry.Stacktrace {
+	if len(stackTrace) == 0 {
+		return nil
+	}
+
+	sentryStackTrace := &sentry.Stacktrace{
+		Frames: make([]sentry.Frame, 0, len(stackTrace)),
+	}
+
+	aggregation := _frameAggregation.Load()
+	omitted := 0
+
+	for i := len(stackTrace) - 1; i >= 0; i-- {
+		if aggregation {
+			fileline := stackTrace[i].File + ":" + strconv.Itoa(stackTrace[i].Line)
+
+			if _, seen := seenTraces[fileline]; seen {
+				omitted++
+				continue
+			}
+
+			seenTraces[fileline] = true
+
+			if omitted > 0 {
+				sentryStackTrace.Frames = append(sentryStackTrace.Frames, sentry.Frame{Function: omittedFrames(omitted)})
+				omitted = 0
+			}
+		}
+
+		frame := sentry.NewFrame(runtime.Frame{
+			Function: stackTrace[i].Function,
+			File:     stackTrace[i].File,
+			Line:     stackTrace[i].Line,
+		})
+		frame.InApp = stackTrace[i].Class == FrameInApp
+		sentryContextLines(&frame)
+
+		sentryStackTrace.Frames = append(sentryStackTrace.Frames, frame)
+	}
+
+	if omitted > 0 {
+		sentryStackTrace.Frames = append(sentryStackTrace.Frames, sentry.Frame{Function: omittedFrames(omitted)})
+	}
+
+	return sentryStackTrace
+}
+
+func (self Error) sentryReport(report *sentry.Event, seenTraces map[string]bool, depth int) {
+	if self.cause != nil && depth >= maxChainDepth() {
+		report.Extra["chain"] = truncatedChain(depth)
+	} else if self.cause != nil {
+		switch cause := self.cause.(type) {
+		case Error:
+			cause.sentryReport(report, seenTraces, depth+1)
+		case *Error:
+			cause.sentryReport(report, seenTraces, depth+1)
+		default:
+			report.Exception = append(report.Exception, sentry.Exception{
+				Type:       strings.TrimPrefix(reflect.TypeOf(cause).String(), "*"),
+				Value:      cause.Error(),
+				Module:     foreignPackage(cause),
+				Stacktrace: sentryStackTrace(foreignStackTrace(cause), seenTraces),
+			})
+		}
+	}
+
+	for key, value := range self.extra {
+		report.Extra[key] = value
+	}
+
+	for key, value := range self.tags {
+		report.Tags[key] = value
+	}
+
+	if self.payload != nil {
+		report.Extra["payload"] = self.payload
+	}
+
+	if self.request != nil {
+		report.Request = &sentry.Request{
+			URL:         self.request.URL,
+			Method:      self.request.Method,
+			Data:        self.request.Data,
+			QueryString: self.request.QueryString,
+			Headers:     self.request.Headers,
+			Env:         self.request.Env,
+		}
+	}
+
+	if self.memStats != nil {
+		report.Extra["memstats"] = self.memStats.String()
+	}
+
+	// The causes are converted first, so their details go after the outer ones
+	if details := self.stringDetails(); len(details) > 0 {
+		previous, _ := report.Extra["details"].([]string)
+		report.Extra["details"] = append(details, previous...)
+	}
+
+	if self.query != nil {
+		report.Extra["query"] = self.query.statement
+		report.Extra["query.args"] = self.query.args
+	}
+
+	for _, breadcrumb := range self.breadcrumbs {
+		report.Breadcrumbs = append(report.Breadcrumbs, &sentry.Breadcrumb{
+			Message:   breadcrumb.message,
+			Data:      breadcrumb.data,
+			Timestamp: breadcrumb.timestamp,
+		})
+	}
+
+	if len(self.goroutineDump) > 0 {
+		report.Attachments = append(report.Attachments, &sentry.Attachment{
+			Filename:    "goroutines.txt",
+			ContentType: "text/plain",
+			Payload:     self.goroutineDump,
+		})
+	}
+
+	for _, attachment := range self.attachments {
+		report.Attachments = append(report.Attachments, &sentry.Attachment{
+			Filename:    attachment.name,
+			ContentType: attachment.contentType,
+			Payload:     attachment.data,
+		})
+	}
+
+	if len(self.observedAt) > 0 {
+		report.Exception = append(report.Exception, sentry.Exception{
+			Type:       self.kind,
+			Value:      "observed here",
+			Module:     self.module,
+			Stacktrace: sentryStackTrace(self.observedAt, seenTraces),
+		})
+	}
+
+	report.Exception = append(report.Exception, sentry.Exception{
+		Type:       self.kind,
+		Value:      self.String(),
+		Module:     self.module,
+		Stacktrace: sentryStackTrace(self.stackTrace, seenTraces),
+	})
+}
+
+// SentryReport returns a Sentry Event containing all the information about the
+// first error and all errors wrapped within itself (including the types, packages
+// messages, stack traces, extra, tags...) and optionally sets a concise message,
+// moving the full string report into the extra (default is false).
+func (self Error) SentryReport(concise ...bool) *sentry.Event {
+	_concise := false
+	if len(concise) > 0 {
+		_concise = concise[0]
+	}
+
+	return self.BuildSentryEvent(SentryEventOptions{Concise: _concise})
+}
+
+// SentrySink returns a Sink that reports the Errors to Sentry with CaptureSentry
+// through the hub (default is the current hub).
+func SentrySink(hub ...*sentry.Hub) Sink {
+	return SinkFunc(func(err error) error {
+		var cerr *Error
+		if !goerrors.As(err, &cerr) {
+			var rerr Error
+			if !goerrors.As(err, &rerr) {
+				return nil
+			}
+
+			cerr = &rerr
+		}
+
+		cerr.CaptureSentry(hub...)
+
+		return nil
+	})
+}
@@ -0,0 +1,173 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrExpected = errors.New("expected business error")
+
+func TestCaptureSentry(t *testing.T) {
+	t.Parallel()
+
+	client, err := sentry.NewClient(sentry.ClientOptions{})
+	if err != nil {
+		t.FailNow()
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	errors.IgnoreKinds(ErrExpected)
+
+	if ErrExpected.Raise().CaptureSentry(hub) != nil {
+		t.FailNow()
+	}
+
+	raised := ErrUserNotFound.Raise("Alex")
+
+	eventID := raised.CaptureSentry(hub)
+	if eventID == nil || raised.SentryEventID() != string(*eventID) {
+		t.FailNow()
+	}
+
+	if !strings.Contains(raised.StringReport(), "Sentry: "+string(*eventID)) {
+		t.FailNow()
+	}
+}
+
+var ErrRetried = errors.New("retried operation failed")
+
+func TestReportLimit(t *testing.T) {
+	t.Parallel()
+
+	var events []*sentry.Event
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	errors.SetReportLimit(ErrRetried, 1, 50*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		ErrRetried.Raise().CaptureSentry(hub)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	ErrRetried.Raise().CaptureSentry(hub)
+
+	if len(events) != 2 || events[1].Extra["suppressed"] != 4 {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestSentryContextLines(t *testing.T) {
+	errors.SetSentryContextLines(2)
+	defer errors.SetSentryContextLines(0)
+
+	report := ErrUserNotFound.Raise("Alex").SentryReport() // context line
+
+	frames := report.Exception[0].Stacktrace.Frames
+	frame := frames[len(frames)-1]
+
+	if !strings.HasSuffix(frame.ContextLine, "// context line") || len(frame.PreContext) != 2 ||
+		len(frame.PostContext) != 2 || frame.PostContext[1] != "\tframes := report.Exception[0].Stacktrace.Frames" {
+		t.FailNow()
+	}
+
+	errors.SetSentryContextLines(0)
+
+	frames = ErrUserNotFound.Raise("Alex").SentryReport().Exception[0].Stacktrace.Frames
+	if frames[len(frames)-1].ContextLine != "" {
+		t.FailNow()
+	}
+}
+
+func TestBuildSentryEvent(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex").Extra(map[string]any{"email": "alex@example.com"})
+
+	event := err.BuildSentryEvent(errors.SentryEventOptions{
+		Concise:     true,
+		Environment: "production",
+		Release:     "v1.2.0",
+		Scrubbers: []func(*sentry.Event) *sentry.Event{
+			func(event *sentry.Event) *sentry.Event {
+				delete(event.Extra, "email")
+				return event
+			},
+		},
+	})
+
+	if event.Environment != "production" || event.Release != "v1.2.0" || event.Message != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if _, ok := event.Extra["email"]; ok || event.Extra["report"] == nil {
+		t.FailNow()
+	}
+
+	dropped := err.BuildSentryEvent(errors.SentryEventOptions{
+		Scrubbers: []func(*sentry.Event) *sentry.Event{func(*sentry.Event) *sentry.Event { return nil }},
+	})
+	if dropped != nil || err.BuildSentryEvent(errors.SentryEventOptions{SampleRate: 0.000001}) != nil {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestSetSentryEventOptions(t *testing.T) {
+	var events []*sentry.Event
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	hub := sentry.NewHub(client, sentry.NewScope())
+
+	errors.SetSentryEventOptions(errors.SentryEventOptions{
+		Environment: "staging",
+		Scrubbers: []func(*sentry.Event) *sentry.Event{
+			func(event *sentry.Event) *sentry.Event {
+				if event.Tags["package"] == "dropped" {
+					return nil
+				}
+
+				return event
+			},
+		},
+	})
+	defer errors.SetSentryEventOptions(errors.SentryEventOptions{})
+
+	ErrUserNotFound.Raise("Alex").CaptureSentry(hub)
+
+	if len(events) != 1 || events[0].Environment != "staging" {
+		t.FailNow()
+	}
+
+	if ErrUserNotFound.WithModule("dropped").Raise("Alex").CaptureSentry(hub) != nil || len(events) != 1 {
+		t.FailNow()
+	}
+}
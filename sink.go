@@ -0,0 +1,116 @@
+package errors
+
+import (
+	goerrors "errors"
+	"io"
+	"sync"
+)
+
+// ErrUnhandled wraps the foreign errors reported to the sinks with Report.
+var ErrUnhandled = New("unhandled error")
+
+// Sink represents a destination of the reported Errors, such as Sentry, log
+// files, chats or metrics.
+type Sink interface {
+	// Write delivers the error to the destination.
+	Write(err error) error
+}
+
+// SinkFunc adapts a function into a Sink.
+type SinkFunc func(err error) error
+
+// Write implements the Sink interface.
+func (self SinkFunc) Write(err error) error {
+	return self(err)
+}
+
+type sink struct {
+	sink     Sink
+	minLevel Level
+}
+
+var _sinks = struct {
+	sync.RWMutex
+	sinks []sink
+}{}
+
+// AddSink adds a destination for the Errors reported with Report whose level is
+// at least minLevel.
+func AddSink(destination Sink, minLevel Level) {
+	_sinks.Lock()
+	defer _sinks.Unlock()
+
+	_sinks.sinks = append(_sinks.sinks, sink{sink: destination, minLevel: minLevel})
+}
+
+// RemoveSinks removes all the destinations added with AddSink.
+func RemoveSinks() {
+	_sinks.Lock()
+	defer _sinks.Unlock()
+
+	_sinks.sinks = nil
+}
+
+// Report delivers the error to every sink added with AddSink according to its
+// level, so call sites don't need to know about each destination. Foreign errors
+// are translated as registered with MapExternal or wrapped into ErrUnhandled.
+// Errors exceeding the limit set with SetReportLimit are not delivered. The
+// delivery errors of the sinks are joined.
+func Report(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	err = mapExternal(err, 1)
+	if value, ok := err.(Error); ok {
+		err = &value
+	}
+
+	escalateError(err)
+
+	var rerr Error
+	if !goerrors.As(err, &rerr) {
+		uerr := ErrUnhandled.Raise().Cause(err).Skip(1)
+		err, rerr = uerr, *uerr
+	}
+
+	if !limitError(err) {
+		return nil
+	}
+
+	_sinks.RLock()
+	defer _sinks.RUnlock()
+
+	var errs []error
+	for _, sink := range _sinks.sinks {
+		if rerr.level < sink.minLevel {
+			continue
+		}
+
+		if werr := sink.sink.Write(err); werr != nil {
+			errs = append(errs, werr)
+		}
+	}
+
+	return goerrors.Join(errs...)
+}
+
+// WriterSink returns a Sink that writes the reports of all errors wrapped within
+// the Errors to the writer with ReportTo, such as a log file or the standard error.
+func WriterSink(writer io.Writer) Sink {
+	var mutex sync.Mutex
+
+	return SinkFunc(func(err error) error {
+		var rerr Error
+		if !goerrors.As(err, &rerr) {
+			return nil
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		_, werr := rerr.ReportTo(writer)
+
+		return werr
+	})
+}
@@ -0,0 +1,85 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"bytes"
+	goerrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+)
+
+// nolint:paralleltest
+func TestSinks(t *testing.T) {
+	defer errors.RemoveSinks()
+
+	var log bytes.Buffer
+	errors.AddSink(errors.WriterSink(&log), errors.LevelWarning)
+
+	var fatal []error
+	errors.AddSink(errors.SinkFunc(func(err error) error {
+		fatal = append(fatal, err)
+		return ErrOtherLibrary
+	}), errors.LevelFatal)
+
+	if errors.Report(ErrCannotDeposit.Raise()) != nil || len(fatal) != 0 {
+		t.FailNow()
+	}
+
+	if !strings.Contains(log.String(), "cannot deposit") || strings.Contains(log.String(), "\x1b") {
+		t.FailNow()
+	}
+
+	if err := errors.Report(ErrDeadlock.Raise()); !goerrors.Is(err, ErrOtherLibrary) || len(fatal) != 1 {
+		t.FailNow()
+	}
+
+	if errors.Report(ErrOtherLibrary) != nil || !strings.Contains(log.String(), "unhandled error") {
+		t.FailNow()
+	}
+
+	if errors.Report(nil) != nil {
+		t.FailNow()
+	}
+}
+
+var ErrSinkFlood = errors.New("sink flood")
+
+// nolint:paralleltest
+func TestSinksReportLimit(t *testing.T) {
+	defer errors.RemoveSinks()
+
+	var events []*sentry.Event
+
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			events = append(events, event)
+			return nil
+		},
+	})
+	if err != nil {
+		t.FailNow()
+	}
+
+	var reported []error
+	errors.AddSink(errors.SinkFunc(func(err error) error {
+		reported = append(reported, err)
+		return nil
+	}), errors.LevelWarning)
+	errors.AddSink(errors.SentrySink(sentry.NewHub(client, sentry.NewScope())), errors.LevelWarning)
+
+	errors.SetReportLimit(ErrSinkFlood, 2, time.Minute)
+
+	for i := 0; i < 5; i++ {
+		_ = errors.Report(ErrSinkFlood.Raise())
+	}
+
+	if len(reported) != 2 || len(events) != 2 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,192 @@
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	_SOURCE_FETCH_TIMEOUT  = 2 * time.Second
+	_SOURCE_FETCH_COOLDOWN = time.Minute
+	_MAX_SOURCE_SIZE       = 1 << 20
+)
+
+// ErrSourceNotFound is returned wrapped by the SourceResolvers when a source file
+// is not available. It is a plain error, so resolving sources doesn't raise errors
+// counted in the stats or delivered to the subscribers.
+var ErrSourceNotFound = goerrors.New("source file not found")
+
+func sourceNotFound(file string, cause error) error {
+	if cause != nil {
+		return fmt.Errorf("%w: %s: %w", ErrSourceNotFound, file, cause)
+	}
+
+	return fmt.Errorf("%w: %s", ErrSourceNotFound, file)
+}
+
+// SourceResolver resolves the content of the source files of the frames rendered
+// with source context, so stripped container images can still show code.
+type SourceResolver interface {
+	Source(file string) ([]byte, error)
+}
+
+// SourceResolverFunc is an adapter to use ordinary functions as SourceResolvers.
+type SourceResolverFunc func(file string) ([]byte, error)
+
+// Source implements the SourceResolver interface.
+func (self SourceResolverFunc) Source(file string) ([]byte, error) {
+	return self(file)
+}
+
+// FileSourceResolver returns a SourceResolver reading the source files from the
+// local filesystem at the paths they were built at (default).
+func FileSourceResolver() SourceResolver {
+	return SourceResolverFunc(os.ReadFile)
+}
+
+// FSSourceResolver returns a SourceResolver reading the source files from the
+// filesystem, such as an embed.FS with the sources of the module, at the paths
+// they were built at relative to the root, such as the module's directory or
+// path when built with -trimpath.
+func FSSourceResolver(fsys fs.FS, root string) SourceResolver {
+	return SourceResolverFunc(func(file string) ([]byte, error) {
+		name, ok := relativeSource(file, root)
+		if !ok {
+			return nil, sourceNotFound(file, nil)
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, sourceNotFound(file, err)
+		}
+
+		return content, nil
+	})
+}
+
+// VCSSourceResolver returns a SourceResolver fetching the source files from the
+// repository at the revision the binary was built at (vcs.revision of the build
+// info) or at the given one, through the format of the URL of the raw files with
+// a %s verb for the revision and another for the path relative to the root, such
+// as "https://raw.githubusercontent.com/org/repo/%s/%s". The files are fetched
+// once, with a timeout of 2 seconds and up to 1 MiB, and no more fetches are made
+// for a minute after the repository cannot be reached, so reports don't block on
+// every frame.
+func VCSSourceResolver(format string, root string, revision ...string) SourceResolver {
+	_revision := ""
+	if len(revision) > 0 {
+		_revision = revision[0]
+	} else if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				_revision = setting.Value
+			}
+		}
+	}
+
+	client := &http.Client{Timeout: _SOURCE_FETCH_TIMEOUT}
+	unreachableUntil := &atomic.Int64{}
+
+	return SourceResolverFunc(func(file string) ([]byte, error) {
+		name, ok := relativeSource(file, root)
+		if !ok || _revision == "" || time.Now().UnixNano() < unreachableUntil.Load() {
+			return nil, sourceNotFound(file, nil)
+		}
+
+		response, err := client.Get(fmt.Sprintf(format, _revision, name))
+		if err != nil {
+			unreachableUntil.Store(time.Now().Add(_SOURCE_FETCH_COOLDOWN).UnixNano())
+			return nil, sourceNotFound(file, err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode != http.StatusOK {
+			return nil, sourceNotFound(file, fmt.Errorf("unexpected status %d", response.StatusCode))
+		}
+
+		content, err := io.ReadAll(io.LimitReader(response.Body, _MAX_SOURCE_SIZE+1))
+		if err != nil {
+			return nil, sourceNotFound(file, err)
+		}
+
+		if len(content) > _MAX_SOURCE_SIZE {
+			return nil, sourceNotFound(file, fmt.Errorf("larger than %d bytes", _MAX_SOURCE_SIZE))
+		}
+
+		return content, nil
+	})
+}
+
+// relativeSource returns the slash-separated path of the file relative to the root.
+func relativeSource(file string, root string) (string, bool) {
+	file = strings.ReplaceAll(file, "\\", "/")
+	root = strings.TrimSuffix(strings.ReplaceAll(root, "\\", "/"), "/")
+
+	if root != "" {
+		if !strings.HasPrefix(file, root+"/") {
+			return "", false
+		}
+
+		file = strings.TrimPrefix(file, root+"/")
+	}
+
+	file = path.Clean(strings.TrimPrefix(file, "/"))
+	if !fs.ValidPath(file) {
+		return "", false
+	}
+
+	return file, true
+}
+
+var _sourceResolver = func() *atomic.Pointer[SourceResolver] {
+	resolver := FileSourceResolver()
+	sourceResolver := &atomic.Pointer[SourceResolver]{}
+	sourceResolver.Store(&resolver)
+
+	return sourceResolver
+}()
+
+var _sourceFiles sync.Map
+
+// SetSourceResolver sets the SourceResolver of the source files rendered in the
+// source context of string reports and sent to Sentry (default is the local
+// filesystem), discarding the already resolved ones.
+func SetSourceResolver(resolver SourceResolver) {
+	if resolver == nil {
+		resolver = FileSourceResolver()
+	}
+
+	_sourceResolver.Store(&resolver)
+
+	_sourceFiles.Range(func(key any, _ any) bool {
+		_sourceFiles.Delete(key)
+		return true
+	})
+}
+
+// sourceLines returns the lines of the source file, resolved once.
+func sourceLines(file string) []string {
+	if lines, ok := _sourceFiles.Load(file); ok {
+		return lines.([]string)
+	}
+
+	content, err := (*_sourceResolver.Load()).Source(file)
+	if err != nil {
+		content = nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	_sourceFiles.Store(file, lines)
+
+	return lines
+}
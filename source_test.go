@@ -0,0 +1,125 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrSourceMissing = errors.New("source missing")
+
+func sourceFile() string {
+	_, file, _, _ := runtime.Caller(0)
+	return file
+}
+
+// nolint:paralleltest
+func TestSetSourceResolver(t *testing.T) {
+	defer errors.SetSourceResolver(nil)
+
+	file := sourceFile()
+	content := strings.Repeat("// embedded\n", 200)
+
+	errors.SetSourceResolver(errors.FSSourceResolver(fstest.MapFS{
+		"source_test.go": &fstest.MapFile{Data: []byte(content)},
+	}, filepath.Dir(file)))
+
+	report := ErrSourceMissing.Raise().Report(errors.ReportOptions{SourceContext: 1})
+	if !strings.Contains(report, "| // embedded\n") {
+		t.FailNow()
+	}
+
+	errors.SetSourceResolver(nil)
+
+	report = ErrSourceMissing.Raise().Report(errors.ReportOptions{SourceContext: 1})
+	if strings.Contains(report, "| // embedded\n") || !strings.Contains(report, "ErrSourceMissing.Raise()") {
+		t.FailNow()
+	}
+}
+
+func TestFSSourceResolver(t *testing.T) {
+	t.Parallel()
+
+	resolver := errors.FSSourceResolver(fstest.MapFS{
+		"billing/invoice.go": &fstest.MapFile{Data: []byte("package billing\n")},
+	}, "/build/app")
+
+	content, err := resolver.Source("/build/app/billing/invoice.go")
+	if err != nil || string(content) != "package billing\n" {
+		t.FailNow()
+	}
+
+	if _, err := resolver.Source("/build/other/billing/invoice.go"); !goerrors.Is(err, errors.ErrSourceNotFound) {
+		t.FailNow()
+	}
+
+	if _, err := resolver.Source("/build/app/billing/missing.go"); !goerrors.Is(err, errors.ErrSourceNotFound) {
+		t.FailNow()
+	}
+}
+
+func TestVCSSourceResolver(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/app/4f2a9c1/billing/invoice.go":
+			fmt.Fprint(w, "package billing\n")
+		case "/org/app/4f2a9c1/billing/generated.go":
+			fmt.Fprint(w, strings.Repeat("// generated\n", 1<<17))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver := errors.VCSSourceResolver(server.URL+"/org/app/%s/%s", "github.com/org/app", "4f2a9c1")
+
+	if content, err := resolver.Source("github.com/org/app/billing/invoice.go"); err != nil ||
+		string(content) != "package billing\n" {
+		t.FailNow()
+	}
+
+	if _, err := resolver.Source("github.com/org/app/billing/missing.go"); !goerrors.Is(err, errors.ErrSourceNotFound) {
+		t.FailNow()
+	}
+
+	if _, err := resolver.Source("github.com/org/app/billing/generated.go"); !goerrors.Is(err, errors.ErrSourceNotFound) {
+		t.FailNow()
+	}
+}
+
+func TestVCSSourceResolverUnreachable(t *testing.T) {
+	t.Parallel()
+
+	var fetches atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		panic(http.ErrAbortHandler)
+	}))
+	defer server.Close()
+
+	resolver := errors.VCSSourceResolver(server.URL+"/org/app/%s/%s", "github.com/org/app", "4f2a9c1")
+
+	for _, file := range []string{"billing/invoice.go", "billing/ledger.go", "billing/refund.go"} {
+		if _, err := resolver.Source("github.com/org/app/" + file); !goerrors.Is(err, errors.ErrSourceNotFound) {
+			t.FailNow()
+		}
+	}
+
+	if fetches.Load() != 1 {
+		t.FailNow()
+	}
+}
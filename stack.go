@@ -0,0 +1,35 @@
+//go:build !tinygo && !errors_headless
+
+package errors
+
+import (
+	"runtime"
+)
+
+const _MAX_GOROUTINE_DUMP_SIZE = 1 << 20
+
+// callers fills the program counters of the calls on the stack, skipping that
+// number of frames as runtime.Callers does from the function calling it.
+func callers(skip int, pcs []uintptr) int {
+	return runtime.Callers(skip+1, pcs)
+}
+
+// unknownModule returns the module of the templates declared where it can't be
+// detected nor was set with NewOptions.Module.
+func unknownModule() string {
+	return "unknown"
+}
+
+// goroutineDump returns the stack traces of all goroutines, truncated to 1MiB.
+func goroutineDump() []byte {
+	buffer := make([]byte, 64<<10)
+
+	for {
+		length := runtime.Stack(buffer, true)
+		if length < len(buffer) || len(buffer) >= _MAX_GOROUTINE_DUMP_SIZE {
+			return buffer[:length]
+		}
+
+		buffer = make([]byte, min(2*len(buffer), _MAX_GOROUTINE_DUMP_SIZE))
+	}
+}
@@ -0,0 +1,169 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const _STATS_RESOLUTION = 10 * time.Second
+
+const _STATS_BUCKETS = 360
+
+type statsBucket struct {
+	slot  int64
+	count int
+}
+
+type kindStats struct {
+	sync.Mutex
+	kind    string
+	module  string
+	buckets [_STATS_BUCKETS]statsBucket
+}
+
+func (self *kindStats) record(now time.Time) {
+	slot := now.UnixNano() / int64(_STATS_RESOLUTION)
+	bucket := &self.buckets[slot%_STATS_BUCKETS]
+
+	self.Lock()
+	defer self.Unlock()
+
+	if bucket.slot != slot {
+		bucket.slot = slot
+		bucket.count = 0
+	}
+
+	bucket.count++
+}
+
+func (self *kindStats) count(now time.Time, window time.Duration) int {
+	last := now.UnixNano() / int64(_STATS_RESOLUTION)
+	first := last - int64((window+_STATS_RESOLUTION-1)/_STATS_RESOLUTION) + 1
+	if first <= last-_STATS_BUCKETS {
+		first = last - _STATS_BUCKETS + 1
+	}
+
+	self.Lock()
+	defer self.Unlock()
+
+	count := 0
+	for _, bucket := range self.buckets {
+		if bucket.slot >= first && bucket.slot <= last {
+			count += bucket.count
+		}
+	}
+
+	return count
+}
+
+// KindCount is the number of Errors of a type raised within a window of time.
+type KindCount struct {
+	Kind   string `json:"kind"`
+	Module string `json:"module"`
+	Count  int    `json:"count"`
+}
+
+// Statistics aggregates the number of raised Errors by type over sliding
+// windows of time of up to an hour, with a resolution of 10 seconds.
+type Statistics struct {
+	kinds sync.Map
+}
+
+var _stats = &Statistics{}
+
+var _statsEnabled = &atomic.Bool{}
+
+// SetStats sets whether to aggregate the raised Errors by type in the Statistics
+// returned by Stats (default is false), so raising doesn't pay for counters that
+// nobody reads.
+func SetStats(enabled bool) {
+	_statsEnabled.Store(enabled)
+}
+
+// Stats returns the in-process aggregator of raised Errors, if enabled with
+// SetStats.
+func Stats() *Statistics {
+	return _stats
+}
+
+func (self *Statistics) record(err *Error) {
+	key := kindKey(err)
+
+	stats, ok := self.kinds.Load(key)
+	if !ok {
+		stats, _ = self.kinds.LoadOrStore(key, &kindStats{kind: err.kind, module: err.module})
+	}
+
+	stats.(*kindStats).record(err.raisedAt)
+}
+
+// Count returns the number of Errors of the template's type raised within the
+// last window of time.
+func (self *Statistics) Count(template Template, window time.Duration) int {
+	stats, ok := self.kinds.Load(kindKey(template.base()))
+	if !ok {
+		return 0
+	}
+
+	return stats.(*kindStats).count(time.Now(), window)
+}
+
+// Top returns the n (all if 0) most raised types of Errors within the last
+// window of time, sorted by descending count.
+func (self *Statistics) Top(n int, window time.Duration) []KindCount {
+	now := time.Now()
+	top := make([]KindCount, 0)
+
+	self.kinds.Range(func(_, value any) bool {
+		stats := value.(*kindStats) // nolint:forcetypeassert
+
+		count := stats.count(now, window)
+		if count > 0 {
+			top = append(top, KindCount{Kind: stats.kind, Module: stats.module, Count: count})
+		}
+
+		return true
+	})
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+
+		return top[i].Module+"."+top[i].Kind < top[j].Module+"."+top[j].Kind
+	})
+
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+
+	return top
+}
+
+// Report renders the n (all if 0) most raised types of Errors within the last
+// window of time as a human-readable summary.
+func (self *Statistics) Report(n int, window time.Duration) string {
+	top := self.Top(n, window)
+
+	var report strings.Builder
+
+	report.WriteString(fmt.Sprintf("Top %d errors in the last %s:\n", len(top), window))
+
+	for i, count := range top {
+		report.WriteString(fmt.Sprintf("%4d. %6d  %s (%s)\n", i+1, count.Count, count.Kind, count.Module))
+	}
+
+	return report.String()
+}
+
+// Reset forgets all the raised Errors.
+func (self *Statistics) Reset() {
+	self.kinds.Range(func(key, _ any) bool {
+		self.kinds.Delete(key)
+		return true
+	})
+}
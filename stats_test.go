@@ -0,0 +1,64 @@
+package errors_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrStatsFrequent = errors.New("frequent stats error")
+var ErrStatsRare = errors.New("rare stats error")
+
+// nolint:paralleltest
+func TestStats(t *testing.T) {
+	errors.SetStats(true)
+	defer errors.SetStats(false)
+
+	// The stats are global, so only the counts raised by this run are compared
+	frequent := errors.Stats().Count(ErrStatsFrequent, time.Minute)
+	rare := errors.Stats().Count(ErrStatsRare, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		_ = ErrStatsFrequent.Raise()
+	}
+
+	_ = ErrStatsRare.Raise()
+
+	if errors.Stats().Count(ErrStatsFrequent, time.Minute)-frequent != 3 {
+		t.FailNow()
+	}
+
+	if errors.Stats().Count(ErrStatsRare, time.Minute)-rare != 1 {
+		t.FailNow()
+	}
+
+	rank := map[string]int{}
+	for i, count := range errors.Stats().Top(0, time.Minute) {
+		rank[count.Kind] = i
+	}
+
+	if rank[ErrStatsFrequent.Kind()] >= rank[ErrStatsRare.Kind()] {
+		t.FailNow()
+	}
+
+	if !strings.Contains(errors.Stats().Report(0, time.Minute), "rare stats error") {
+		t.FailNow()
+	}
+
+	if len(errors.Stats().Top(1, time.Minute)) != 1 {
+		t.FailNow()
+	}
+}
+
+// nolint:paralleltest
+func TestStatsDisabled(t *testing.T) {
+	count := errors.Stats().Count(ErrStatsRare, time.Minute)
+
+	_ = ErrStatsRare.Raise()
+
+	if errors.Stats().Count(ErrStatsRare, time.Minute) != count {
+		t.FailNow()
+	}
+}
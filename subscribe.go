@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+const _SUBSCRIPTION_BUFFER = 256
+
+// Filter selects the raised Errors streamed to a subscriber, such as by type or
+// level (nil selects all of them).
+type Filter func(err *Error) bool
+
+type subscription struct {
+	channel chan<- *Error
+	filter  Filter
+	mutex   sync.Mutex
+	ring    [_SUBSCRIPTION_BUFFER]*Error
+	head    int
+	size    int
+	wake    chan struct{}
+	done    chan struct{}
+}
+
+func (self *subscription) push(err *Error) {
+	self.mutex.Lock()
+
+	if self.size == _SUBSCRIPTION_BUFFER {
+		self.head = (self.head + 1) % _SUBSCRIPTION_BUFFER
+		self.size--
+	}
+
+	self.ring[(self.head+self.size)%_SUBSCRIPTION_BUFFER] = err
+	self.size++
+
+	self.mutex.Unlock()
+
+	select {
+	case self.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (self *subscription) pop() *Error {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if self.size == 0 {
+		return nil
+	}
+
+	err := self.ring[self.head]
+	self.ring[self.head] = nil
+	self.head = (self.head + 1) % _SUBSCRIPTION_BUFFER
+	self.size--
+
+	return err
+}
+
+func (self *subscription) run() {
+	for {
+		select {
+		case <-self.done:
+			return
+		case <-self.wake:
+		}
+
+		for err := self.pop(); err != nil; err = self.pop() {
+			select {
+			case self.channel <- err:
+			case <-self.done:
+				return
+			}
+		}
+	}
+}
+
+var _subscriptions = struct {
+	sync.Mutex
+	list atomic.Pointer[[]*subscription]
+}{}
+
+// Subscribe streams the raised Errors selected by the filter to the channel, for
+// in-process consumers such as health checkers flipping readiness on repeated
+// fatal errors, adaptive circuit breakers or tests asserting on raised errors,
+// returning a function that cancels the subscription. The Errors are snapshots
+// taken when raised, before their extra, tags or causes are added, and are
+// buffered in a ring of 256 that drops the oldest ones if the consumer falls
+// behind, so raising never blocks.
+func Subscribe(channel chan<- *Error, filter Filter) func() {
+	subscriber := &subscription{
+		channel: channel,
+		filter:  filter,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+
+	_subscriptions.Lock()
+	defer _subscriptions.Unlock()
+
+	list := []*subscription{subscriber}
+	if current := _subscriptions.list.Load(); current != nil {
+		list = append(list, *current...)
+	}
+
+	_subscriptions.list.Store(&list)
+
+	go subscriber.run()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			_subscriptions.Lock()
+			defer _subscriptions.Unlock()
+
+			list := make([]*subscription, 0)
+			for _, other := range *_subscriptions.list.Load() {
+				if other != subscriber {
+					list = append(list, other)
+				}
+			}
+
+			if len(list) == 0 {
+				_subscriptions.list.Store(nil)
+			} else {
+				_subscriptions.list.Store(&list)
+			}
+
+			close(subscriber.done)
+		})
+	}
+}
+
+// publish streams a snapshot of the raised Error to the subscribers.
+func publish(err *Error) {
+	list := _subscriptions.list.Load()
+	if list == nil {
+		return
+	}
+
+	for _, subscription := range *list {
+		snapshot := *err
+		snapshot.tags = maps.Clone(err.tags)
+
+		if subscription.filter == nil || subscription.filter(&snapshot) {
+			subscription.push(&snapshot)
+		}
+	}
+}
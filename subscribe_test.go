@@ -0,0 +1,80 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neoxelox/errors"
+)
+
+var (
+	ErrSubscribed   = errors.New("subscribed %d")
+	ErrUnsubscribed = errors.New("unsubscribed")
+)
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	raised := make(chan *errors.Error)
+	unsubscribe := errors.Subscribe(raised, func(err *errors.Error) bool {
+		return ErrSubscribed.Is(err)
+	})
+
+	ErrUnsubscribed.Raise()
+	ErrSubscribed.Raise(1).Extra(map[string]any{"mutated": true})
+	ErrSubscribed.Raise(2)
+
+	for _, expected := range []string{"subscribed 1", "subscribed 2"} {
+		select {
+		case err := <-raised:
+			if err.Error() != expected || err.Extras()["mutated"] != nil {
+				t.FailNow()
+			}
+		case <-time.After(time.Second):
+			t.FailNow()
+		}
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	ErrSubscribed.Raise(3)
+
+	select {
+	case <-raised:
+		t.FailNow()
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestSubscribeDropsOldest(t *testing.T) {
+	t.Parallel()
+
+	kind := errors.New("dropped %d")
+
+	raised := make(chan *errors.Error)
+	unsubscribe := errors.Subscribe(raised, func(err *errors.Error) bool {
+		return kind.Is(err)
+	})
+	defer unsubscribe()
+
+	for i := 0; i < 1000; i++ {
+		kind.Raise(i)
+	}
+
+	last := ""
+	for {
+		select {
+		case err := <-raised:
+			last = err.Error()
+			continue
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		break
+	}
+
+	if last != "dropped 999" {
+		t.FailNow()
+	}
+}
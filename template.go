@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"context"
+)
+
+// Template represents a declared type of Error, which can only be raised into
+// new Error instances, so the methods mutating raised Errors (With, Extra,
+// Cause...) cannot be called on the shared template by mistake.
+type Template struct {
+	template *Error
+}
+
+// _undeclared is the Error of the zero Template, such as the one returned by
+// Lookup for undeclared types, so its methods are safe to call.
+var _undeclared = &Error{}
+
+// base returns the Error the template was declared as.
+func (self Template) base() *Error {
+	if self.template == nil {
+		return _undeclared
+	}
+
+	return self.template
+}
+
+// Raise creates a new Error instance formatting its message if
+// needed and optionally captures its stack trace.
+func (self Template) Raise(args ...any) *Error {
+	return self.base().raise(3, sprintf(self.base().message, args))
+}
+
+// RaiseLazy creates a new Error instance like Raise but deferring the formatting of
+// its message until it is actually read, for errors that are usually matched by
+// type and discarded without rendering their message.
+func (self Template) RaiseLazy(args ...any) *Error {
+	err := self.base().raise(3, self.base().message)
+	err.args = append(make([]any, 0, len(args)), args...)
+
+	return err
+}
+
+// RaiseMessage creates a new Error instance like Raise but with an already formatted
+// message, such as one received from another service for the same Error's type.
+func (self Template) RaiseMessage(message string) *Error {
+	return self.base().raise(3, message)
+}
+
+// RaiseCtx creates a new Error instance like Raise and tags it with the tags
+// extracted from the context by the taggers added with AddContextTagger.
+func (self Template) RaiseCtx(ctx context.Context, args ...any) *Error {
+	return tagContext(ctx, self.base().raise(3, sprintf(self.base().message, args)))
+}
+
+// WithModule returns a copy of the template declared within another package,
+// overriding the one detected by New, such as to group errors by service in
+// monorepos.
+func (self Template) WithModule(module string) Template {
+	template := *self.base()
+	template.module = module
+	template.key = module + "." + template.kind
+
+	self.template = &template
+	_registry.Store(kindKey(&template), self)
+
+	return self
+}
+
+// Docs returns a copy of the template linking to the URL of its documentation
+// or runbook, shown in the reports, API bodies and Sentry events of its Errors,
+// so on-call engineers land directly on it.
+func (self Template) Docs(url string) Template {
+	template := *self.base()
+	template.docs = url
+
+	self.template = &template
+	_registry.Store(kindKey(&template), self)
+
+	return self
+}
+
+// DocsURL returns the URL of the documentation or runbook of the template, if any.
+func (self Template) DocsURL() string {
+	return self.base().docs
+}
+
+// Level returns the severity of the Errors of the template.
+func (self Template) Level() Level {
+	return self.base().level
+}
+
+// Message returns the message of the template without formatting.
+func (self Template) Message() string {
+	return self.base().message
+}
+
+// Kind returns the type of the Errors of the template.
+func (self Template) Kind() string {
+	return self.base().kind
+}
+
+// Code returns the stable textual identifier of the Errors of the template.
+func (self Template) Code() string {
+	return self.base().code
+}
+
+// Module returns the package where the template was declared.
+func (self Template) Module() string {
+	return self.base().module
+}
+
+// DeclaredAt returns the file:line where the template was declared with New, as
+// listed in the family catalogs (see ErrorFamily.Errors) and the registry (see
+// Lookup).
+func (self Template) DeclaredAt() string {
+	return self.base().declaredAt
+}
+
+// Is checks whether an error is of the template's type. The standard library's
+// errors.Is matches it through Target.
+func (self Template) Is(err error) bool {
+	return self.base().Is(err)
+}
+
+// In checks whether an Error of the template's type is wrapped inside an error.
+func (self Template) In(err error) bool {
+	return self.base().In(err)
+}
+
+// target represents the template as the target of the standard library's
+// errors.Is, which is not an error itself so templates can't be returned as
+// errors without being raised.
+type target struct {
+	template *Error
+}
+
+// Error implements the Error interface.
+func (self target) Error() string {
+	return self.template.message
+}
+
+// Target returns the target of the standard library's errors.Is matching the
+// Errors of the template's type, such as errors.Is(err, ErrUserNotFound.Target()).
+func (self Template) Target() error {
+	return target{template: self.base()}
+}
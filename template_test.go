@@ -0,0 +1,117 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/neoxelox/errors"
+)
+
+func TestTemplate(t *testing.T) {
+	t.Parallel()
+
+	err := ErrUserNotFound.Raise("Alex")
+	if !ErrUserNotFound.Is(err) || !goerrors.Is(err, ErrUserNotFound.Target()) || ErrCannotDeposit.Is(err) {
+		t.FailNow()
+	}
+
+	if !ErrUserNotFound.In(ErrCannotDeposit.Raise().Cause(err)) || err.Error() != "user Alex not found" {
+		t.FailNow()
+	}
+
+	if ErrUserNotFound.Kind() != "user %s not found" || ErrUserNotFound.Target().Error() != "user %s not found" {
+		t.FailNow()
+	}
+
+	template, ok := errors.Lookup(ErrUserNotFound.Module(), ErrUserNotFound.Kind())
+	if !ok || !template.Is(ErrUserNotFound.RaiseCtx(context.Background(), "Alex")) {
+		t.FailNow()
+	}
+
+	if lazy := ErrUserNotFound.RaiseLazy("Alex"); lazy.Error() != "user Alex not found" {
+		t.FailNow()
+	}
+}
+
+func TestTemplateZero(t *testing.T) {
+	t.Parallel()
+
+	template, ok := errors.Lookup("undeclared", "undeclared")
+	if ok || template.Kind() != "" || template.Is(ErrUserNotFound.Raise("Alex")) {
+		t.FailNow()
+	}
+
+	if err := template.Raise(); err == nil || err.Error() != "" || ErrUserNotFound.Is(err) {
+		t.FailNow()
+	}
+}
+
+var ErrRunbook = errors.NewWithOptions("runbook", errors.NewOptions{Code: "RUNBOOK"}).
+	Docs("https://runbooks.example.com/RUNBOOK")
+
+func TestTemplateDocs(t *testing.T) {
+	t.Parallel()
+
+	err := ErrRunbook.Raise()
+	if ErrRunbook.DocsURL() != "https://runbooks.example.com/RUNBOOK" || err.DocsURL() != ErrRunbook.DocsURL() {
+		t.FailNow()
+	}
+
+	if !strings.Contains(err.StringReport(), "    See: https://runbooks.example.com/RUNBOOK\n") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Tags["docs"] != ErrRunbook.DocsURL() {
+		t.FailNow()
+	}
+
+	body, _ := err.APIBody(1)
+	if !strings.Contains(string(body), `"docs_url":"https://runbooks.example.com/RUNBOOK"`) {
+		t.FailNow()
+	}
+
+	if ErrUserNotFound.DocsURL() != "" || strings.Contains(ErrUserNotFound.Raise("Alex").StringReport(), "See:") {
+		t.FailNow()
+	}
+}
+
+var ErrCannotTransfer = errors.New("cannot transfer").Require("userID", "accountID")
+
+// nolint:paralleltest
+func TestTemplateRequire(t *testing.T) {
+	err := ErrCannotTransfer.Raise().Extra(map[string]any{"userID": 42})
+	if missing := err.MissingExtras(); len(missing) != 1 || missing[0] != "accountID" {
+		t.FailNow()
+	}
+
+	if strings.Contains(err.Report(errors.ReportOptions{}), "Missing extras") {
+		t.FailNow()
+	}
+
+	errors.SetRequireExtras(true)
+	defer errors.SetRequireExtras(false)
+
+	if !strings.Contains(err.Report(errors.ReportOptions{}), "    Missing extras: accountID\n") {
+		t.FailNow()
+	}
+
+	if err.SentryReport().Tags["missing_extras"] != "accountID" {
+		t.FailNow()
+	}
+
+	data, _ := err.MarshalBinary()
+
+	var decoded errors.Error
+	if decoded.UnmarshalBinary(data) != nil || len(decoded.MissingExtras()) != 1 {
+		t.FailNow()
+	}
+
+	wrapped := ErrCannotTransfer.Raise().Cause(ErrUserNotFound.Raise("Alex").Extra(map[string]any{"accountID": 7}))
+	if len(wrapped.Extra(map[string]any{"userID": 42}).MissingExtras()) != 0 || ErrCannotDeposit.Raise().MissingExtras() != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd) || tinygo
+
+package errors
+
+import (
+	"os"
+)
+
+// terminalWidth returns whether the file is opened on a character device, such as
+// a terminal, as its size can't be queried on this platform (0 means unknown).
+func terminalWidth(file *os.File) (int, bool) {
+	info, err := file.Stat()
+
+	return 0, err == nil && info.Mode()&os.ModeCharDevice != 0
+}
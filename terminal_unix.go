@@ -0,0 +1,26 @@
+//go:build (darwin || dragonfly || freebsd || linux || netbsd || openbsd) && !tinygo
+
+package errors
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// terminalWidth returns the width in columns of the terminal the file is opened
+// on, or false if it is not a terminal, such as a pipe, a regular file or
+// /dev/null.
+func terminalWidth(file *os.File) (int, bool) {
+	var size struct {
+		rows, columns, xpixel, ypixel uint16
+	}
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(&size))) // nolint:gosec
+	if errno != 0 {
+		return 0, false
+	}
+
+	return int(size.columns), true
+}
@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"sync/atomic"
+)
+
+// Theme represents the ANSI styles of the parts of colored string reports. Empty
+// styles render the part unstyled.
+type Theme struct {
+	// Headline is the style of the first line with all the messages.
+	Headline string
+	// Message is the style of the message of each error.
+	Message string
+	// Frame is the style of each frame of the stack traces.
+	Frame string
+	// Extra is the style of the extra information of each error.
+	Extra string
+}
+
+var (
+	// DarkTheme is the default theme, readable on dark terminal backgrounds.
+	DarkTheme = Theme{Headline: _COLOR_BRIGHT_RED, Message: _COLOR_RED}
+	// LightTheme is a theme readable on light terminal backgrounds.
+	LightTheme = Theme{Headline: _COLOR_BOLD_RED, Message: _COLOR_RED, Frame: _COLOR_BLUE, Extra: _COLOR_MAGENTA}
+	// MonochromeTheme is a theme without colors that only uses bold and dim text.
+	MonochromeTheme = Theme{Headline: _COLOR_BOLD, Message: _COLOR_BOLD, Extra: _COLOR_DIM}
+)
+
+var _theme = func() *atomic.Pointer[Theme] {
+	theme := &atomic.Pointer[Theme]{}
+	theme.Store(ptr(DarkTheme))
+
+	return theme
+}()
+
+// SetTheme sets the theme of colored string reports (default is DarkTheme).
+func SetTheme(theme Theme) {
+	_theme.Store(&theme)
+}
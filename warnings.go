@@ -0,0 +1,10 @@
+package errors
+
+// Warnings accumulates the non-fatal issues of an operation that partially
+// succeeds, to be reported alongside a nil error.
+type Warnings []*Error
+
+// Add appends a raised Error to the Warnings setting its level to warning.
+func (self *Warnings) Add(warning *Error) {
+	*self = append(*self, warning.AsWarning())
+}
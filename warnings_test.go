@@ -0,0 +1,36 @@
+//go:build !tinygo && !errors_headless
+
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/neoxelox/errors"
+)
+
+var ErrStaleCache = errors.NewWarning("cache for %s is stale")
+
+func TestWarnings(t *testing.T) {
+	t.Parallel()
+
+	var warnings errors.Warnings
+
+	warnings.Add(ErrStaleCache.Raise("users"))
+	warnings.Add(ErrUserNotFound.Raise("Alex"))
+
+	if len(warnings) != 2 {
+		t.FailNow()
+	}
+
+	for _, warning := range warnings {
+		if warning.Level() != errors.LevelWarning || warning.SentryReport().Level != sentry.LevelWarning {
+			t.FailNow()
+		}
+	}
+
+	if ErrUserNotFound.Raise("Alex").Level() != errors.LevelError {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type xmlDetail struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlCause struct {
+	Code    string `xml:"code,omitempty"`
+	Type    string `xml:"type,omitempty"`
+	Message string `xml:"message"`
+}
+
+type xmlFault struct {
+	XMLName xml.Name    `xml:"fault"`
+	Code    string      `xml:"code"`
+	Message string      `xml:"message"`
+	Details []xmlDetail `xml:"details>detail,omitempty"`
+	Causes  []xmlCause  `xml:"causes>cause,omitempty"`
+}
+
+func (self Error) xmlFault() xmlFault {
+	fault := xmlFault{
+		Code:    apiBodyCode(self),
+		Message: self.formatted(),
+	}
+
+	for key, value := range self.extra {
+		fault.Details = append(fault.Details, xmlDetail{Key: key, Value: fmt.Sprintf("%v", value)})
+	}
+
+	sort.Slice(fault.Details, func(i, j int) bool {
+		return fault.Details[i].Key < fault.Details[j].Key
+	})
+
+	cause := self.cause
+	for depth := 1; cause != nil && depth < maxChainDepth(); depth++ {
+		switch err := cause.(type) {
+		case Error:
+			fault.Causes = append(fault.Causes, xmlCause{Code: apiBodyCode(err), Message: err.formatted()})
+			cause = err.cause
+		case *Error:
+			fault.Causes = append(fault.Causes, xmlCause{Code: apiBodyCode(*err), Message: err.formatted()})
+			cause = err.cause
+		default:
+			fault.Causes = append(fault.Causes, xmlCause{
+				Type:    strings.TrimPrefix(reflect.TypeOf(err).String(), "*"),
+				Message: err.Error(),
+			})
+			cause = nil
+		}
+	}
+
+	return fault
+}
+
+// MarshalXML implements the XMLMarshaler interface.
+func (self Error) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	return encoder.EncodeElement(self.xmlFault(), start)
+}
+
+// XMLReport returns an XML fault document containing the code, message, extra
+// (as details) and wrapped errors (as causes) of the Error, for SOAP-style and
+// legacy integrations.
+func (self Error) XMLReport() string {
+	report, _ := xml.MarshalIndent(self.xmlFault(), "", "  ")
+
+	return xml.Header + string(report)
+}
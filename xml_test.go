@@ -0,0 +1,42 @@
+package errors_test
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestXMLReport(t *testing.T) {
+	t.Parallel()
+
+	err := ErrPaymentDeclined.Raise().
+		Extra(map[string]any{"amount": 42, "currency": "EUR"}).
+		Cause(ErrUserNotFound.Raise("Alex").Cause(ErrOtherLibrary))
+
+	expected := xml.Header + `<fault>
+  <code>PAYMENT_DECLINED</code>
+  <message>payment declined</message>
+  <details>
+    <detail key="amount">42</detail>
+    <detail key="currency">EUR</detail>
+  </details>
+  <causes>
+    <cause>
+      <code>UNKNOWN</code>
+      <message>user Alex not found</message>
+    </cause>
+    <cause>
+      <type>errors.errorString</type>
+      <message>other library error</message>
+    </cause>
+  </causes>
+</fault>`
+	if err.XMLReport() != expected {
+		t.FailNow()
+	}
+
+	marshaled, merr := xml.Marshal(err)
+	if merr != nil || !strings.HasPrefix(string(marshaled), "<Error><code>PAYMENT_DECLINED</code>") {
+		t.FailNow()
+	}
+}